@@ -16,6 +16,7 @@ import (
 	"github.com/xmidt-org/bascule"
 	"github.com/xmidt-org/bascule/basculehttp"
 	"github.com/xmidt-org/clortho"
+	"github.com/xmidt-org/tr1d1um/transaction"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
@@ -38,9 +39,11 @@ type JWTValidator struct {
 
 }
 
-// JWTToken implements bascule.Token
+// JWTToken implements bascule.Token and bascule.AttributesAccessor, giving
+// access to the claims of the JWT such as its partner IDs.
 type JWTToken struct {
 	principal string
+	claims    jwt.MapClaims
 }
 
 // Principal returns the subject claim from the JWT
@@ -48,6 +51,12 @@ func (jt *JWTToken) Principal() string {
 	return jt.principal
 }
 
+// Get returns a claim of the JWT
+func (jt *JWTToken) Get(key string) (interface{}, bool) {
+	v, ok := jt.claims[key]
+	return v, ok
+}
+
 func provideAuthChain() fx.Option {
 	return fx.Options(
 		fx.Provide(
@@ -55,9 +64,8 @@ func provideAuthChain() fx.Option {
 			func(c JWTValidator) clortho.Config {
 				return c.Config
 			},
-			func(config clortho.Config, logger *zap.Logger) (*basculehttp.Middleware, error) {
-				return createAuthMiddleware(config, logger)
-			},
+			newKeyResolver,
+			newAuthMiddleware,
 			fx.Annotated{
 				Name: "auth_chain",
 				Target: func(middleware *basculehttp.Middleware) alice.Chain {
@@ -68,16 +76,28 @@ func provideAuthChain() fx.Option {
 	)
 }
 
-// createAuthMiddleware creates a properly configured Bascule middleware with JWT support
-func createAuthMiddleware(config clortho.Config, logger *zap.Logger) (*basculehttp.Middleware, error) {
-	// Create Clortho resolver for JWT key
+// newKeyResolver creates the Clortho resolver for JWT keys
+func newKeyResolver(config clortho.Config) (clortho.Resolver, error) {
 	resolver, err := clortho.NewResolver(
 		clortho.WithConfig(config),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create JWT key resolver: %w", err)
 	}
+	return resolver, nil
+}
+
+// createAuthMiddleware creates a properly configured Bascule middleware with JWT support
+func createAuthMiddleware(config clortho.Config, logger *zap.Logger) (*basculehttp.Middleware, error) {
+	resolver, err := newKeyResolver(config)
+	if err != nil {
+		return nil, err
+	}
+	return newAuthMiddleware(resolver, logger)
+}
 
+// newAuthMiddleware creates the Bascule middleware, resolving JWT keys with resolver
+func newAuthMiddleware(resolver clortho.Resolver, logger *zap.Logger) (*basculehttp.Middleware, error) {
 	// Create JWT token parser
 	jwtParser := &JWTTokenParser{
 		resolver: resolver,
@@ -207,7 +227,7 @@ func (jtp *JWTTokenParser) Parse(ctx context.Context, raw string) (bascule.Token
 		} else if username, ok := claims["username"].(string); ok {
 			principal = username
 		} else {
-			principal = "unknown"
+			principal = transaction.UnknownPrincipal
 		}
 	}
 
@@ -216,5 +236,6 @@ func (jtp *JWTTokenParser) Parse(ctx context.Context, raw string) (bascule.Token
 
 	return &JWTToken{
 		principal: principal,
+		claims:    claims,
 	}, nil
 }
@@ -8,14 +8,23 @@ import (
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/xmidt-org/bascule"
 	"github.com/xmidt-org/clortho"
+	"github.com/xmidt-org/tr1d1um/group"
+	"github.com/xmidt-org/tr1d1um/schedule"
+	"github.com/xmidt-org/tr1d1um/transaction"
 	"go.uber.org/zap"
 )
 
@@ -143,6 +152,7 @@ func TestJWTTokenParser_Parse(t *testing.T) {
 		expectErr   error
 		expectUser  string
 		expectKeyID string
+		expectPIDs  []string
 	}{
 		{
 			name:      "missing raw token",
@@ -156,6 +166,25 @@ func TestJWTTokenParser_Parse(t *testing.T) {
 			expectUser:  "alice",
 			expectKeyID: "kid-sub",
 		},
+		{
+			name:        "partner IDs from the claims",
+			raw:         signToken(t, jwt.SigningMethodRS256, jwt.MapClaims{"sub": "alice", "partner-id": []string{"comcast"}}, "kid-partner", privateKey),
+			resolverKey: &mockClorthoKey{keyID: "kid-partner", public: &privateKey.PublicKey},
+			expectUser:  "alice",
+			expectKeyID: "kid-partner",
+			expectPIDs:  []string{"comcast"},
+		},
+		{
+			name: "partner IDs from the allowed resources claim",
+			raw: signToken(t, jwt.SigningMethodRS256, jwt.MapClaims{
+				"sub":              "alice",
+				"allowedResources": map[string]interface{}{"allowedPartners": []string{"comcast", "sky"}},
+			}, "kid-resources", privateKey),
+			resolverKey: &mockClorthoKey{keyID: "kid-resources", public: &privateKey.PublicKey},
+			expectUser:  "alice",
+			expectKeyID: "kid-resources",
+			expectPIDs:  []string{"comcast", "sky"},
+		},
 		{
 			name:        "fallback to user claim",
 			raw:         signToken(t, jwt.SigningMethodRS256, jwt.MapClaims{"user": "bob"}, "kid-user", privateKey),
@@ -227,6 +256,9 @@ func TestJWTTokenParser_Parse(t *testing.T) {
 				assert.NoError(t, err)
 				require.NotNil(t, tok)
 				assert.Equal(t, tc.expectUser, tok.Principal())
+
+				partnerIDs, _ := transaction.TokenPartnerIDs(tok)
+				assert.Equal(t, tc.expectPIDs, partnerIDs)
 			}
 
 			if tc.expectKeyID != "" {
@@ -235,3 +267,163 @@ func TestJWTTokenParser_Parse(t *testing.T) {
 		})
 	}
 }
+
+type subjectExchanger struct {
+	subject transaction.Subject
+}
+
+func (e *subjectExchanger) Exchange(_ context.Context, s transaction.Subject) (string, error) {
+	e.subject = s
+	return "Bearer exchanged", nil
+}
+
+func TestJWTTokenExchange(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	parser := &JWTTokenParser{
+		resolver: &mockResolver{key: &mockClorthoKey{keyID: "kid", public: &privateKey.PublicKey}},
+		logger:   zap.NewNop(),
+	}
+	raw := signToken(t, jwt.SigningMethodRS256, jwt.MapClaims{"sub": "alice", "partner-id": []string{"comcast"}}, "kid", privateKey)
+	tok, err := parser.Parse(context.Background(), raw)
+	require.NoError(t, err)
+
+	exchanger := &subjectExchanger{}
+	auth := transaction.OutboundAuth{
+		Policy:    transaction.AuthPolicy{Default: transaction.AuthModeExchange},
+		Exchanger: exchanger,
+	}
+	authorization, err := auth.Authorization(bascule.WithToken(context.Background(), tok), transaction.APIDevice, "config", "Bearer "+raw)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer exchanged", authorization)
+	assert.Equal(t, transaction.Subject{Principal: "alice", PartnerIDs: []string{"comcast"}, Token: raw}, exchanger.subject)
+}
+
+// newJWTTestChain returns an auth chain putting the token parsed by a real
+// JWTTokenParser in the request context, and a function signing tokens for it.
+func newJWTTestChain(t *testing.T) (alice.Chain, func(jwt.MapClaims) string) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	parser := &JWTTokenParser{
+		resolver: &mockResolver{key: &mockClorthoKey{keyID: "kid", public: &privateKey.PublicKey}},
+		logger:   zap.NewNop(),
+	}
+	chain := alice.New(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tok, err := parser.Parse(r.Context(), strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+			if err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(bascule.WithToken(r.Context(), tok)))
+		})
+	})
+
+	return chain, func(claims jwt.MapClaims) string {
+		return "Bearer " + signToken(t, jwt.SigningMethodRS256, claims, "kid", privateKey)
+	}
+}
+
+func TestJWTTokenJobScope(t *testing.T) {
+	chain, sign := newJWTTestChain(t)
+
+	store, err := schedule.NewFileStore("")
+	require.NoError(t, err)
+	router := mux.NewRouter()
+	schedule.ConfigHandler(&schedule.Options{
+		S:                           schedule.NewScheduler(schedule.Config{}, nil, transaction.OutboundAuth{Acquirer: &transaction.BasicAcquirer{Token: "Basic xyz=="}}, store, zap.NewNop()),
+		APIRouter:                   router,
+		Authenticate:                &chain,
+		Log:                         zap.NewNop(),
+		ValidServices:               func() []string { return []string{"config"} },
+		ReducedLoggingResponseCodes: func() []int { return nil },
+		BearerFingerprint:           func() transaction.FingerprintConfig { return transaction.FingerprintConfig{} },
+	})
+
+	serve := func(method, path, authorization string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "http://localhost"+path, strings.NewReader(`{"service": "config", "devices": ["mac:112233445566"], "command": {"command": "DELETE_ROW", "row": "Device.T.1."}, "at": "2026-11-02T02:00:00Z"}`))
+		r.Header.Set("Authorization", authorization)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+	create := func(authorization string) schedule.Job {
+		w := serve(http.MethodPost, "/jobs", authorization)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var job schedule.Job
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+		return job
+	}
+
+	var (
+		alice     = sign(jwt.MapClaims{"sub": "alice", "partner-id": []string{"comcast"}})
+		bob       = sign(jwt.MapClaims{"sub": "bob", "partner-id": []string{"comcast", "sky"}})
+		anonymous = sign(jwt.MapClaims{"partner-id": []string{"sky"}})
+		other     = sign(jwt.MapClaims{"partner-id": []string{"other"}})
+	)
+
+	// jobs run for the partners of the token
+	job := create(alice)
+	assert.Equal(t, "alice", job.Owner)
+	assert.Equal(t, []string{"comcast"}, job.PartnerIDs)
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/jobs/"+job.ID, bob).Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/jobs/"+job.ID, other).Code)
+
+	// tokens without a subject don't share their jobs through their principal
+	job = create(anonymous)
+	assert.Equal(t, transaction.UnknownPrincipal, job.Owner)
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/jobs/"+job.ID, bob).Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/jobs/"+job.ID, other).Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodDelete, "/jobs/"+job.ID, other).Code)
+}
+
+func TestJWTTokenGroupScope(t *testing.T) {
+	chain, sign := newJWTTestChain(t)
+
+	store, err := group.NewFileStore("")
+	require.NoError(t, err)
+	router := mux.NewRouter()
+	group.ConfigHandler(&group.Options{
+		R: group.NewRegistry(group.Config{}, store, group.StaticInventory{
+			{ID: "mac:112233445566", PartnerIDs: []string{"comcast"}},
+			{ID: "mac:112233445577", PartnerIDs: []string{"sky"}},
+		}),
+		APIRouter:                   router,
+		Authenticate:                &chain,
+		Log:                         zap.NewNop(),
+		ReducedLoggingResponseCodes: func() []int { return nil },
+		BearerFingerprint:           func() transaction.FingerprintConfig { return transaction.FingerprintConfig{} },
+	})
+
+	serve := func(method, path, authorization, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "http://localhost"+path, strings.NewReader(body))
+		r.Header.Set("Authorization", authorization)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	var (
+		alice = sign(jwt.MapClaims{"sub": "alice", "partner-id": []string{"comcast"}})
+		bob   = sign(jwt.MapClaims{"sub": "bob"})
+	)
+
+	// selectors only choose devices of the partners of the token
+	w := serve(http.MethodPost, "/groups", alice, `{"name": "lab", "selector": {"prefixes": ["mac:1122"]}}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var g group.Group
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &g))
+	assert.Equal(t, "alice", g.Owner)
+	assert.Equal(t, []string{"comcast"}, g.PartnerIDs)
+
+	w = serve(http.MethodGet, "/groups/lab/devices", alice, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"group": "lab", "devices": ["mac:112233445566"]}`, w.Body.String())
+
+	// tokens without partners can't have selectors
+	w = serve(http.MethodPost, "/groups", bob, `{"name": "other", "selector": {"prefixes": ["mac:1122"]}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/groups/lab", bob, "").Code)
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+)
+
+const (
+	configSourceDefault = "default"
+	configSourceFile    = "file"
+
+	maskedValue = "****"
+)
+
+var (
+	// secretSections are config sections whose values are all masked.
+	secretSections = []string{
+		"authx",
+		webhookConfigKey + ".basicclientconfig.auth",
+	}
+
+	// secretNames are the names of keys whose values are masked, wherever
+	// they appear.
+	secretNames = map[string]bool{
+		"basic":         true,
+		"clientsecret":  true,
+		"password":      true,
+		"secret":        true,
+		"token":         true,
+		"apikey":        true,
+		"authorization": true,
+	}
+)
+
+// effectiveValue is a resolved config value and where it came from.
+type effectiveValue struct {
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"`
+}
+
+type configDumpRoutesIn struct {
+	fx.In
+	Router *mux.Router `name:"server_pprof"`
+	V      *viper.Viper
+}
+
+// isSecret reports whether the value of the given lower case key is masked.
+func isSecret(key string) bool {
+	for _, s := range secretSections {
+		if key == s || strings.HasPrefix(key, s+".") {
+			return true
+		}
+	}
+
+	return secretNames[key[strings.LastIndex(key, ".")+1:]]
+}
+
+// mask hides a secret value. Empty values are left alone so that unset
+// secrets can be told apart from set ones.
+func mask(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	if rv := reflect.ValueOf(value); rv.IsZero() ||
+		((rv.Kind() == reflect.Slice || rv.Kind() == reflect.Map) && rv.Len() == 0) {
+		return value
+	}
+	return maskedValue
+}
+
+// configSource returns where viper resolved the given key from, the config
+// file taking precedence over the defaults.
+func configSource(v *viper.Viper, key string) string {
+	if v.InConfig(key) {
+		return configSourceFile
+	}
+	return configSourceDefault
+}
+
+// effectiveConfig returns every resolved config value, keyed by its lower case
+// key path, with secrets masked.
+func effectiveConfig(v *viper.Viper) map[string]effectiveValue {
+	config := make(map[string]effectiveValue)
+	for _, key := range v.AllKeys() {
+		value := v.Get(key)
+		if isSecret(key) {
+			value = mask(value)
+		}
+		config[key] = effectiveValue{
+			Value:  value,
+			Source: configSource(v, key),
+		}
+	}
+	return config
+}
+
+// configDumpHandler serves the effective config as JSON.
+func configDumpHandler(v *viper.Viper) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(effectiveConfig(v))
+	})
+}
+
+func handleConfigDumpRoutes(in configDumpRoutesIn) {
+	if in.Router != nil {
+		in.Router.Handle("/debug/config", configDumpHandler(in.V)).Methods(http.MethodGet)
+	}
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSecret(t *testing.T) {
+	tcs := []struct {
+		key      string
+		expected bool
+	}{
+		{key: "authacquirer.basic", expected: true},
+		{key: "authacquirer.clientcredentials.clientsecret", expected: true},
+		{key: "authacquirer.clientcredentials.clientid"},
+		{key: "authx", expected: true},
+		{key: "authx.inbound.basic", expected: true},
+		{key: "webhook.basicclientconfig.auth.jwt.authurl", expected: true},
+		{key: "webhook.basicclientconfig.address"},
+		{key: "authxyz"},
+		{key: "targeturl"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.key, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isSecret(tc.key))
+		})
+	}
+}
+
+func TestConfigDumpHandler(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tr1d1um.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`
+targetURL: "http://scytale:6300/api/v3"
+supportedServices: ["config"]
+authAcquirer:
+  Basic: "Basic dXNlcjpwYXNz"
+  clientCredentials:
+    clientID: "tr1d1um"
+    clientSecret: ""
+authx:
+  inbound:
+    basic: ["dXNlcjpwYXNz"]
+webhook:
+  BasicClientConfig:
+    address: "http://localhost:6600"
+    auth:
+      basic: "Basic dXNlcjpwYXNz"
+`), 0600))
+
+	v := newViper()
+	v.SetConfigFile(file)
+	require.NoError(t, v.ReadInConfig())
+
+	router := mux.NewRouter()
+	handleConfigDumpRoutes(configDumpRoutesIn{Router: router, V: v})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/config", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var config map[string]effectiveValue
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &config))
+
+	expected := map[string]effectiveValue{
+		"targeturl":          {Value: "http://scytale:6300/api/v3", Source: configSourceFile},
+		"hooksscheme":        {Value: "https", Source: configSourceDefault},
+		"authacquirer.basic": {Value: maskedValue, Source: configSourceFile},
+		"authacquirer.clientcredentials.clientid":     {Value: "tr1d1um", Source: configSourceFile},
+		"authacquirer.clientcredentials.clientsecret": {Value: "", Source: configSourceFile},
+		"authx.inbound.basic":                         {Value: maskedValue, Source: configSourceFile},
+		"webhook.basicclientconfig.address":           {Value: "http://localhost:6600", Source: configSourceFile},
+		"webhook.basicclientconfig.auth.basic":        {Value: maskedValue, Source: configSourceFile},
+	}
+	for key, e := range expected {
+		assert.Equal(t, e, config[key], key)
+	}
+	assert.NotContains(t, rec.Body.String(), "dXNlcjpwYXNz")
+}
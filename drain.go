@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/viper"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	drainKey = "drain"
+
+	defaultDrainTimeout = 10 * time.Second
+)
+
+// drainConfig configures how in-flight requests to XMiDT are drained when
+// tr1d1um stops.
+type drainConfig struct {
+	// Timeout is how long in-flight requests may take to finish. Those still
+	// running after it are aborted.
+	// (Optional) Defaults to 10s.
+	Timeout time.Duration
+}
+
+type drainIn struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Logger    *zap.Logger
+	Config    drainConfig `name:"drain"`
+	Drainer   *transaction.Drainer
+	Aborted   prometheus.Counter `name:"drain_aborted_requests"`
+}
+
+// drainTimeout returns the configured drain timeout.
+func drainTimeout(v *viper.Viper) time.Duration {
+	if d := v.GetDuration(drainKey + ".timeout"); d > 0 {
+		return d
+	}
+	return defaultDrainTimeout
+}
+
+// handleDrain drains the in-flight requests to XMiDT when the app stops.
+// It must be invoked after the servers are created so that it runs before
+// they are shut down.
+func handleDrain(in drainIn) {
+	timeout := in.Config.Timeout
+	if timeout <= 0 {
+		timeout = defaultDrainTimeout
+	}
+
+	in.Lifecycle.Append(fx.StopHook(func(ctx context.Context) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		in.Logger.Info("draining in-flight requests", zap.Duration("timeout", timeout))
+		start := time.Now()
+		aborted := in.Drainer.Drain(ctx)
+		if aborted > 0 {
+			in.Aborted.Add(float64(aborted))
+			in.Logger.Warn("aborted in-flight requests", zap.Int("aborted", aborted))
+		}
+		in.Logger.Info("drained in-flight requests", zap.Duration("duration", time.Since(start)))
+	}))
+}
@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package group
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/xmidt-org/candlelight"
+	"github.com/xmidt-org/tr1d1um/translation"
+)
+
+// memberResult is the response of a member of a group to a request targeting
+// the group. JSON bodies are kept as is, other bodies are kept as strings.
+type memberResult struct {
+	StatusCode int             `json:"statusCode"`
+	Body       json.RawMessage `json:"body,omitempty"`
+}
+
+type fanOutResponse struct {
+	Group   string                  `json:"group"`
+	Devices map[string]memberResult `json:"devices"`
+}
+
+// fanOut serves a request targeting a group as one request per member of the
+// group, sent through router.
+type fanOut struct {
+	registry   *Registry
+	router     http.Handler
+	bodyLimits translation.BodyLimits
+}
+
+// fanOutMiddleware returns a middleware of the API router handing requests
+// whose {deviceid} is a group target to h. Group targets are only supported
+// by the methods of routes lists, keyed by path template, other requests
+// targeting groups are rejected.
+func fanOutMiddleware(routes map[string][]string, h http.Handler) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(mux.Vars(r)["deviceid"], TargetPrefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var template string
+			if route := mux.CurrentRoute(r); route != nil {
+				template, _ = route.GetPathTemplate()
+			}
+			if !slices.Contains(routes[template], r.Method) {
+				encodeError(r.Context(), ErrUnsupportedTarget, w)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ServeHTTP responds with the result of every member, with a 200 status code
+// when all members succeeded and a 207 one otherwise.
+func (f *fanOut) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	target := mux.Vars(r)["deviceid"]
+	name := strings.TrimPrefix(target, TargetPrefix)
+
+	if _, err := getGroup(ctx, f.registry, name); err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+	members, err := f.registry.Members(ctx, name)
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+
+	var body []byte
+	if r.Body != nil {
+		limit := f.bodyLimits.Limit(r.Method)
+		body, err = io.ReadAll(io.LimitReader(r.Body, limit+1))
+		if err == nil && int64(len(body)) > limit {
+			err = translation.ErrBodyTooLarge
+		}
+		if err != nil {
+			encodeError(ctx, err, w)
+			return
+		}
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, f.registry.config.Concurrency)
+		results = make(map[string]memberResult, len(members))
+	)
+	for _, member := range members {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := f.serveMember(r, target, member, body)
+			mu.Lock()
+			results[member] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	code := http.StatusOK
+	for _, result := range results {
+		if result.StatusCode < 200 || result.StatusCode > 299 {
+			code = http.StatusMultiStatus
+			break
+		}
+	}
+
+	w.Header().Set(contentTypeHeaderKey, "application/json")
+	w.Header().Set(candlelight.HeaderWPATIDKeyName, getTID(ctx))
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(&fanOutResponse{Group: name, Devices: results})
+}
+
+// serveMember sends a copy of r, with target replaced by member in its path,
+// through the router. The copy keeps the transaction ID of r.
+func (f *fanOut) serveMember(r *http.Request, target, member string, body []byte) memberResult {
+	req := r.Clone(r.Context())
+	req.URL.Path = strings.Replace(r.URL.Path, "/"+target+"/", "/"+member+"/", 1)
+	req.URL.RawPath = ""
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	if tid := getTID(r.Context()); tid != "" {
+		req.Header.Set(candlelight.HeaderWPATIDKeyName, tid)
+	}
+
+	rw := &memberWriter{header: make(http.Header)}
+	f.router.ServeHTTP(rw, req)
+	return rw.result()
+}
+
+// memberWriter records the response of a member.
+type memberWriter struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (w *memberWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *memberWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+}
+
+func (w *memberWriter) Write(p []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(p)
+}
+
+func (w *memberWriter) result() memberResult {
+	result := memberResult{StatusCode: w.code}
+	if result.StatusCode == 0 {
+		result.StatusCode = http.StatusOK
+	}
+
+	body := bytes.TrimSpace(w.body.Bytes())
+	switch {
+	case len(body) == 0:
+	case json.Valid(body):
+		result.Body = body
+	default:
+		result.Body, _ = json.Marshal(string(body))
+	}
+
+	return result
+}
@@ -0,0 +1,291 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package group
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+// TargetPrefix marks a group name used in place of a device ID, as in
+// /device/group:{name}/config.
+const TargetPrefix = "group:"
+
+// Defaults for Config.
+const (
+	DefaultMaxMembers  = 1000
+	DefaultConcurrency = 10
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// Group errors
+var (
+	ErrInvalidName      = transaction.NewBadRequestError(errors.New("group name must be 1 to 64 letters, digits, '_', '.' or '-'"))
+	ErrMissingMembers   = transaction.NewBadRequestError(errors.New("group must have devices or a selector"))
+	ErrInvalidSelector  = transaction.NewBadRequestError(errors.New("selector must have prefixes or partnerIDs"))
+	ErrUnscopedSelector = transaction.NewBadRequestError(errors.New("selectors are only supported for tokens with partners"))
+	ErrTooManyMembers   = transaction.NewBadRequestError(errors.New("group has too many members"))
+	ErrGroupExists      = transaction.NewCodedError(errors.New("group already exists"), http.StatusConflict)
+)
+
+// Config configures device groups.
+type Config struct {
+	// File is the path of the file groups are saved to.
+	// (Optional) By default, groups are kept in memory only.
+	File string
+
+	// Inventory is the list of devices group selectors choose members from.
+	// (Optional) By default, groups only have their listed devices.
+	Inventory []Device
+
+	// MaxMembers is the maximum number of members of a group.
+	// (Optional) Defaults to DefaultMaxMembers.
+	MaxMembers int
+
+	// Concurrency is the maximum number of member requests in flight for a
+	// request targeting a group.
+	// (Optional) Defaults to DefaultConcurrency.
+	Concurrency int
+}
+
+func (c Config) withDefaults() Config {
+	if c.MaxMembers <= 0 {
+		c.MaxMembers = DefaultMaxMembers
+	}
+	if c.Concurrency <= 0 {
+		c.Concurrency = DefaultConcurrency
+	}
+	return c
+}
+
+// Group is a named set of devices, listed explicitly, chosen by a selector
+// or both.
+type Group struct {
+	Name     string    `json:"name"`
+	Devices  []string  `json:"devices,omitempty"`
+	Selector *Selector `json:"selector,omitempty"`
+
+	// Owner and PartnerIDs are the principal and the partners of the token
+	// of the group's creator. Selectors only choose devices of these
+	// partners, so groups with a selector must have partners.
+	Owner      string   `json:"owner,omitempty"`
+	PartnerIDs []string `json:"partnerIDs,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Selector chooses the devices of the inventory whose ID starts with one of
+// Prefixes and which belong to one of PartnerIDs. Empty lists match all
+// devices.
+type Selector struct {
+	Prefixes   []string `json:"prefixes,omitempty"`
+	PartnerIDs []string `json:"partnerIDs,omitempty"`
+}
+
+func (s Selector) matches(d Device) bool {
+	id := strings.ToLower(d.ID)
+	if len(s.Prefixes) > 0 && !slices.ContainsFunc(s.Prefixes, func(p string) bool {
+		return strings.HasPrefix(id, strings.ToLower(p))
+	}) {
+		return false
+	}
+
+	if len(s.PartnerIDs) > 0 && !slices.ContainsFunc(s.PartnerIDs, func(p string) bool {
+		return slices.Contains(d.PartnerIDs, p)
+	}) {
+		return false
+	}
+
+	return true
+}
+
+// Device is a device of the inventory.
+type Device struct {
+	ID         string   `json:"id"`
+	PartnerIDs []string `json:"partnerIDs,omitempty"`
+}
+
+// Inventory provides the devices group selectors choose members from.
+type Inventory interface {
+	Devices(context.Context) ([]Device, error)
+}
+
+// StaticInventory is an Inventory of a fixed list of devices.
+type StaticInventory []Device
+
+// Devices returns the devices of the inventory.
+func (i StaticInventory) Devices(context.Context) ([]Device, error) {
+	return i, nil
+}
+
+// Registry manages groups and resolves their members.
+type Registry struct {
+	config    Config
+	store     Store
+	inventory Inventory
+	now       func() time.Time
+
+	// mu serializes the changes of groups.
+	mu sync.Mutex
+}
+
+// NewRegistry returns a Registry of the groups of store, whose selectors
+// choose members from inventory.
+func NewRegistry(c Config, store Store, inventory Inventory) *Registry {
+	return &Registry{
+		config:    c.withDefaults(),
+		store:     store,
+		inventory: inventory,
+		now:       time.Now,
+	}
+}
+
+// Create validates and saves a new group.
+func (r *Registry) Create(g Group) (Group, error) {
+	g, err := r.validate(g)
+	if err != nil {
+		return Group{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err = r.store.Get(g.Name); err == nil {
+		return Group{}, ErrGroupExists
+	} else if !errors.Is(err, ErrGroupNotFound) {
+		return Group{}, err
+	}
+
+	g.CreatedAt = r.now()
+	g.UpdatedAt = g.CreatedAt
+	return g, r.store.Put(g)
+}
+
+// Update validates and saves the new definition of an existing group, which
+// keeps its owner and partners.
+func (r *Registry) Update(g Group) (Group, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, err := r.store.Get(g.Name)
+	if err != nil {
+		return Group{}, err
+	}
+
+	g.Owner, g.PartnerIDs = previous.Owner, previous.PartnerIDs
+	g, err = r.validate(g)
+	if err != nil {
+		return Group{}, err
+	}
+
+	g.CreatedAt = previous.CreatedAt
+	g.UpdatedAt = r.now()
+	return g, r.store.Put(g)
+}
+
+// Get returns the group with the given name.
+func (r *Registry) Get(name string) (Group, error) {
+	return r.store.Get(name)
+}
+
+// List returns all groups.
+func (r *Registry) List() ([]Group, error) {
+	return r.store.List()
+}
+
+// Delete removes the group with the given name.
+func (r *Registry) Delete(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.store.Delete(name)
+}
+
+// Members returns the sorted device IDs of the group with the given name: its
+// listed devices and the devices of the inventory of its partners its
+// selector chooses.
+func (r *Registry) Members(ctx context.Context, name string) ([]string, error) {
+	g, err := r.store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	members := slices.Clone(g.Devices)
+	if g.Selector != nil && len(g.PartnerIDs) > 0 && r.inventory != nil {
+		devices, err := r.inventory.Devices(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range devices {
+			deviceID, err := wrp.ParseDeviceID(d.ID)
+			if err != nil || !g.Selector.matches(Device{ID: string(deviceID), PartnerIDs: d.PartnerIDs}) {
+				continue
+			}
+			if !slices.ContainsFunc(d.PartnerIDs, func(p string) bool {
+				return slices.Contains(g.PartnerIDs, p)
+			}) {
+				continue
+			}
+			members = append(members, string(deviceID))
+		}
+	}
+
+	slices.Sort(members)
+	members = slices.Compact(members)
+	if len(members) > r.config.MaxMembers {
+		return nil, ErrTooManyMembers
+	}
+
+	return members, nil
+}
+
+// validate checks the name and members of a group, returning it with its
+// devices canonicalized and deduplicated.
+func (r *Registry) validate(g Group) (Group, error) {
+	if !namePattern.MatchString(g.Name) {
+		return Group{}, ErrInvalidName
+	}
+
+	if len(g.Devices) == 0 && g.Selector == nil {
+		return Group{}, ErrMissingMembers
+	}
+
+	if g.Selector != nil && len(g.Selector.Prefixes) == 0 && len(g.Selector.PartnerIDs) == 0 {
+		return Group{}, ErrInvalidSelector
+	}
+
+	if g.Selector != nil && len(g.PartnerIDs) == 0 {
+		return Group{}, ErrUnscopedSelector
+	}
+
+	devices := make([]string, 0, len(g.Devices))
+	for _, id := range g.Devices {
+		deviceID, err := wrp.ParseDeviceID(id)
+		if err != nil {
+			return Group{}, transaction.NewBadRequestError(err)
+		}
+		if !slices.Contains(devices, string(deviceID)) {
+			devices = append(devices, string(deviceID))
+		}
+	}
+
+	if len(devices) > r.config.MaxMembers {
+		return Group{}, ErrTooManyMembers
+	}
+
+	g.Devices = devices
+	return g, nil
+}
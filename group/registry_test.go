@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package group
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testNow       = time.Date(2026, 11, 1, 2, 0, 0, 0, time.UTC)
+	testInventory = StaticInventory{
+		{ID: "mac:112233445566", PartnerIDs: []string{"comcast"}},
+		{ID: "MAC:11-22-33-44-55-77", PartnerIDs: []string{"sky"}},
+		{ID: "mac:aabbccddeeff", PartnerIDs: []string{"comcast"}},
+		{ID: "invalid"},
+	}
+)
+
+func newTestRegistry(t *testing.T, c Config) *Registry {
+	store, err := NewFileStore("")
+	require.NoError(t, err)
+
+	r := NewRegistry(c, store, testInventory)
+	r.now = func() time.Time { return testNow }
+	return r
+}
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		description string
+		group       Group
+		expectedErr string
+	}{
+		{
+			description: "invalid name",
+			group:       Group{Name: "group:a", Devices: []string{"mac:112233445566"}},
+			expectedErr: ErrInvalidName.Error(),
+		},
+		{
+			description: "no members",
+			group:       Group{Name: "a"},
+			expectedErr: ErrMissingMembers.Error(),
+		},
+		{
+			description: "empty selector",
+			group:       Group{Name: "a", Selector: &Selector{}, PartnerIDs: []string{"comcast"}},
+			expectedErr: ErrInvalidSelector.Error(),
+		},
+		{
+			description: "selector without partners",
+			group:       Group{Name: "a", Selector: &Selector{Prefixes: []string{"mac:1122"}}},
+			expectedErr: ErrUnscopedSelector.Error(),
+		},
+		{
+			description: "invalid device",
+			group:       Group{Name: "a", Devices: []string{"nope"}},
+			expectedErr: "invalid device name",
+		},
+		{
+			description: "too many devices",
+			group:       Group{Name: "a", Devices: []string{"mac:112233445566", "mac:aabbccddeeff"}},
+			expectedErr: ErrTooManyMembers.Error(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := newTestRegistry(t, Config{MaxMembers: 1}).Create(tc.group)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedErr)
+		})
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		r := newTestRegistry(t, Config{})
+		g, err := r.Create(Group{Name: "lab", Devices: []string{"MAC:11-22-33-44-55-66", "mac:112233445566"}})
+		require.NoError(err)
+		assert.Equal([]string{"mac:112233445566"}, g.Devices)
+		assert.Equal(testNow, g.CreatedAt)
+
+		_, err = r.Create(Group{Name: "lab", Devices: []string{"mac:aabbccddeeff"}})
+		assert.Equal(ErrGroupExists, err)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	r := newTestRegistry(t, Config{})
+	_, err := r.Update(Group{Name: "lab", Devices: []string{"mac:112233445566"}})
+	assert.Equal(ErrGroupNotFound, err)
+
+	_, err = r.Create(Group{Name: "lab", Devices: []string{"mac:112233445566"}, Owner: "user-1", PartnerIDs: []string{"sky"}})
+	require.NoError(err)
+
+	r.now = func() time.Time { return testNow.Add(time.Hour) }
+	g, err := r.Update(Group{Name: "lab", Selector: &Selector{PartnerIDs: []string{"sky"}}, Owner: "user-2", PartnerIDs: []string{"comcast"}})
+	require.NoError(err)
+	assert.Empty(g.Devices)
+	assert.Equal("user-1", g.Owner)
+	assert.Equal([]string{"sky"}, g.PartnerIDs)
+	assert.Equal(testNow, g.CreatedAt)
+	assert.Equal(testNow.Add(time.Hour), g.UpdatedAt)
+
+	_, err = r.Create(Group{Name: "devices", Devices: []string{"mac:112233445566"}, Owner: "user-1"})
+	require.NoError(err)
+	_, err = r.Update(Group{Name: "devices", Selector: &Selector{Prefixes: []string{"mac:1122"}}, PartnerIDs: []string{"comcast"}})
+	assert.Equal(ErrUnscopedSelector, err)
+}
+
+func TestMembers(t *testing.T) {
+	tests := []struct {
+		description string
+		group       Group
+		expected    []string
+	}{
+		{
+			description: "devices",
+			group:       Group{Name: "a", Devices: []string{"mac:aabbccddeeff", "mac:112233445566"}},
+			expected:    []string{"mac:112233445566", "mac:aabbccddeeff"},
+		},
+		{
+			description: "prefix",
+			group:       Group{Name: "a", Selector: &Selector{Prefixes: []string{"MAC:11223344"}}, PartnerIDs: []string{"comcast", "sky"}},
+			expected:    []string{"mac:112233445566", "mac:112233445577"},
+		},
+		{
+			description: "partner",
+			group:       Group{Name: "a", Selector: &Selector{PartnerIDs: []string{"comcast"}}, PartnerIDs: []string{"comcast", "sky"}},
+			expected:    []string{"mac:112233445566", "mac:aabbccddeeff"},
+		},
+		{
+			description: "prefix and partner",
+			group:       Group{Name: "a", Selector: &Selector{Prefixes: []string{"mac:1122"}, PartnerIDs: []string{"comcast"}}, PartnerIDs: []string{"comcast", "sky"}},
+			expected:    []string{"mac:112233445566"},
+		},
+		{
+			description: "prefix of the group partners",
+			group:       Group{Name: "a", Selector: &Selector{Prefixes: []string{"mac:11223344"}}, PartnerIDs: []string{"sky"}},
+			expected:    []string{"mac:112233445577"},
+		},
+		{
+			description: "devices and selector",
+			group:       Group{Name: "a", Devices: []string{"mac:112233445566", "uuid:1234"}, Selector: &Selector{PartnerIDs: []string{"comcast"}}, PartnerIDs: []string{"comcast"}},
+			expected:    []string{"mac:112233445566", "mac:aabbccddeeff", "uuid:1234"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			r := newTestRegistry(t, Config{})
+			_, err := r.Create(tc.group)
+			require.NoError(t, err)
+
+			members, err := r.Members(context.Background(), tc.group.Name)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, members)
+		})
+	}
+
+	t.Run("too many members", func(t *testing.T) {
+		r := newTestRegistry(t, Config{MaxMembers: 1})
+		_, err := r.Create(Group{Name: "a", Selector: &Selector{PartnerIDs: []string{"comcast"}}, PartnerIDs: []string{"comcast"}})
+		require.NoError(t, err)
+
+		_, err = r.Members(context.Background(), "a")
+		assert.Equal(t, ErrTooManyMembers, err)
+	})
+
+	t.Run("unknown group", func(t *testing.T) {
+		_, err := newTestRegistry(t, Config{}).Members(context.Background(), "a")
+		assert.Equal(t, ErrGroupNotFound, err)
+	})
+}
@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package group
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/xmidt-org/tr1d1um/transaction"
+)
+
+// ErrGroupNotFound is returned for unknown group names.
+var ErrGroupNotFound = transaction.NewCodedError(errors.New("group not found"), http.StatusNotFound)
+
+// Store persists groups.
+type Store interface {
+	// Get returns the group with the given name, or ErrGroupNotFound.
+	Get(name string) (Group, error)
+
+	// List returns all groups.
+	List() ([]Group, error)
+
+	// Put creates or replaces a group.
+	Put(Group) error
+
+	// Delete removes the group with the given name, or returns
+	// ErrGroupNotFound.
+	Delete(name string) error
+}
+
+// FileStore is a Store keeping groups in memory and saving them to a JSON
+// file on every change.
+type FileStore struct {
+	path string
+
+	mu     sync.Mutex
+	groups map[string]Group
+}
+
+// NewFileStore returns a FileStore saving groups to the file at path, loading
+// the groups the file already has. An empty path keeps groups in memory only.
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{
+		path:   path,
+		groups: make(map[string]Group),
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var groups []Group
+	if err = json.Unmarshal(data, &groups); err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		s.groups[g.Name] = g
+	}
+
+	return s, nil
+}
+
+// Get returns the group with the given name.
+func (s *FileStore) Get(name string) (Group, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.groups[name]
+	if !ok {
+		return Group{}, ErrGroupNotFound
+	}
+	return g, nil
+}
+
+// List returns all groups, ordered by name.
+func (s *FileStore) List() ([]Group, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.list(), nil
+}
+
+func (s *FileStore) list() []Group {
+	groups := make([]Group, 0, len(s.groups))
+	for _, name := range slices.Sorted(maps.Keys(s.groups)) {
+		groups = append(groups, s.groups[name])
+	}
+	return groups
+}
+
+// Put creates or replaces a group and saves the groups to the file. The
+// group is left unchanged if they can't be saved.
+func (s *FileStore) Put(g Group) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, existed := s.groups[g.Name]
+	s.groups[g.Name] = g
+
+	if err := s.save(); err != nil {
+		if existed {
+			s.groups[g.Name] = previous
+		} else {
+			delete(s.groups, g.Name)
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete removes a group and saves the groups to the file. The group is kept
+// if they can't be saved.
+func (s *FileStore) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, ok := s.groups[name]
+	if !ok {
+		return ErrGroupNotFound
+	}
+	delete(s.groups, name)
+
+	if err := s.save(); err != nil {
+		s.groups[name] = previous
+		return err
+	}
+	return nil
+}
+
+// save writes the groups to a temporary file first, so that the file is never
+// left partially written.
+func (s *FileStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.list(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
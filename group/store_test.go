@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package group
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		s, err := NewFileStore("")
+		require.NoError(err)
+
+		_, err = s.Get("a")
+		assert.Equal(ErrGroupNotFound, err)
+
+		require.NoError(s.Put(Group{Name: "b"}))
+		require.NoError(s.Put(Group{Name: "a"}))
+		require.NoError(s.Put(Group{Name: "a", Devices: []string{"mac:112233445566"}}))
+
+		g, err := s.Get("a")
+		require.NoError(err)
+		assert.Equal([]string{"mac:112233445566"}, g.Devices)
+
+		groups, err := s.List()
+		require.NoError(err)
+		require.Len(groups, 2)
+		assert.Equal("a", groups[0].Name)
+		assert.Equal("b", groups[1].Name)
+
+		require.NoError(s.Delete("a"))
+		assert.Equal(ErrGroupNotFound, s.Delete("a"))
+	})
+
+	t.Run("file", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		path := filepath.Join(t.TempDir(), "groups.json")
+		s, err := NewFileStore(path)
+		require.NoError(err)
+
+		require.NoError(s.Put(Group{Name: "a", Selector: &Selector{Prefixes: []string{"mac:1122"}}}))
+		require.NoError(s.Put(Group{Name: "b", Devices: []string{"mac:112233445566"}}))
+		require.NoError(s.Delete("b"))
+
+		reloaded, err := NewFileStore(path)
+		require.NoError(err)
+		groups, err := reloaded.List()
+		require.NoError(err)
+		require.Len(groups, 1)
+		assert.Equal(&Selector{Prefixes: []string{"mac:1122"}}, groups[0].Selector)
+
+		_, err = os.Stat(path + ".tmp")
+		assert.True(os.IsNotExist(err))
+	})
+
+	t.Run("save failure", func(t *testing.T) {
+		s, err := NewFileStore(filepath.Join(t.TempDir(), "missing", "groups.json"))
+		require.NoError(t, err)
+
+		assert.Error(t, s.Put(Group{Name: "a"}))
+		_, err = s.Get("a")
+		assert.Equal(t, ErrGroupNotFound, err)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "groups.json")
+		require.NoError(t, os.WriteFile(path, []byte("{"), 0600))
+
+		_, err := NewFileStore(path)
+		assert.Error(t, err)
+	})
+}
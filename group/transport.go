@@ -0,0 +1,268 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package group
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/xmidt-org/candlelight"
+	"github.com/xmidt-org/sallust"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/tr1d1um/translation"
+	"go.uber.org/zap"
+)
+
+const contentTypeHeaderKey = "Content-Type"
+
+// maxRequestSize is the maximum size, in bytes, of group requests.
+const maxRequestSize = 1024 * 1024
+
+// Group request errors
+var (
+	ErrInvalidGroup      = transaction.NewBadRequestError(errors.New("group is invalid"))
+	ErrUnsupportedTarget = transaction.NewBadRequestError(errors.New("groups can only be targeted by GET and SET of device parameters and by device stat requests"))
+)
+
+// Options wraps the properties needed to set up the group server
+type Options struct {
+	R *Registry
+
+	//APIRouter is assumed to be a subrouter with the API prefix path (i.e. 'api/v2')
+	APIRouter                   *mux.Router
+	Authenticate                *alice.Chain
+	Log                         *zap.Logger
+	ReducedLoggingResponseCodes func() []int
+	BearerFingerprint           func() transaction.FingerprintConfig
+
+	// BodyLimits bounds the bodies of requests targeting groups.
+	BodyLimits translation.BodyLimits
+}
+
+// groupRequest is the body of group requests.
+type groupRequest struct {
+	Name     string    `json:"name"`
+	Devices  []string  `json:"devices"`
+	Selector *Selector `json:"selector"`
+}
+
+type groupsResponse struct {
+	Groups []Group `json:"groups"`
+}
+
+type membersResponse struct {
+	Group   string   `json:"group"`
+	Devices []string `json:"devices"`
+}
+
+// ConfigHandler sets up the server that powers the group endpoints:
+// POST /groups creates a group, GET /groups lists them, GET /groups/{name}
+// returns one, PUT /groups/{name} replaces it, DELETE /groups/{name} removes it
+// and GET /groups/{name}/devices returns its members. GET and SET requests of
+// device parameters and device stat requests using group:{name} in place of a
+// device ID are sent to every member of the group. Callers only see and use
+// their own groups and the groups of their partners.
+func ConfigHandler(c *Options) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(transaction.ErrorLogEncoder(sallust.Get, encodeError)),
+		kithttp.ServerFinalizer(transaction.LogFunc(c.ReducedLoggingResponseCodes)),
+	}
+
+	createHandler := kithttp.NewServer(
+		func(ctx context.Context, request interface{}) (interface{}, error) {
+			g := request.(Group)
+			caller := transaction.GetCaller(ctx)
+			g.Owner, g.PartnerIDs = caller.Principal, caller.PartnerIDs
+			return c.R.Create(g)
+		},
+		decodeGroupRequest,
+		encodeGroupResponse(http.StatusCreated),
+		opts...,
+	)
+
+	listHandler := kithttp.NewServer(
+		func(ctx context.Context, _ interface{}) (interface{}, error) {
+			groups, err := c.R.List()
+			if err != nil {
+				return nil, err
+			}
+
+			caller := transaction.GetCaller(ctx)
+			resp := &groupsResponse{Groups: []Group{}}
+			for _, g := range groups {
+				if caller.Allowed(g.Owner, g.PartnerIDs) {
+					resp.Groups = append(resp.Groups, g)
+				}
+			}
+			return resp, nil
+		},
+		kithttp.NopRequestDecoder,
+		encodeGroupResponse(http.StatusOK),
+		opts...,
+	)
+
+	getHandler := kithttp.NewServer(
+		func(ctx context.Context, request interface{}) (interface{}, error) {
+			return getGroup(ctx, c.R, request.(string))
+		},
+		decodeGroupName,
+		encodeGroupResponse(http.StatusOK),
+		opts...,
+	)
+
+	updateHandler := kithttp.NewServer(
+		func(ctx context.Context, request interface{}) (interface{}, error) {
+			g := request.(Group)
+			if _, err := getGroup(ctx, c.R, g.Name); err != nil {
+				return nil, err
+			}
+			return c.R.Update(g)
+		},
+		decodeGroupRequest,
+		encodeGroupResponse(http.StatusOK),
+		opts...,
+	)
+
+	deleteHandler := kithttp.NewServer(
+		func(ctx context.Context, request interface{}) (interface{}, error) {
+			name := request.(string)
+			if _, err := getGroup(ctx, c.R, name); err != nil {
+				return nil, err
+			}
+			return map[string]string{"name": name}, c.R.Delete(name)
+		},
+		decodeGroupName,
+		encodeGroupResponse(http.StatusOK),
+		opts...,
+	)
+
+	membersHandler := kithttp.NewServer(
+		membersEndpoint(c.R),
+		decodeGroupName,
+		encodeGroupResponse(http.StatusOK),
+		opts...,
+	)
+
+	welcome := transaction.WelcomeFunc(c.BearerFingerprint)
+	chain := func(h http.Handler) http.Handler {
+		return c.Authenticate.Then(candlelight.EchoFirstTraceNodeInfo(candlelight.Tracing{}.Propagator(), false)(welcome(h)))
+	}
+	handle := func(path string, h http.Handler, methods ...string) *mux.Route {
+		return c.APIRouter.Handle(path, chain(h)).Methods(methods...)
+	}
+
+	groups := handle("/groups", createHandler, http.MethodPost)
+	handle("/groups", listHandler, http.MethodGet)
+	handle("/groups/{name}", getHandler, http.MethodGet)
+	handle("/groups/{name}", updateHandler, http.MethodPut)
+	handle("/groups/{name}", deleteHandler, http.MethodDelete)
+	handle("/groups/{name}/devices", membersHandler, http.MethodGet)
+
+	// the device routes are registered with the same prefix as the group ones
+	template, _ := groups.GetPathTemplate()
+	prefix := strings.TrimSuffix(template, "/groups")
+	c.APIRouter.Use(fanOutMiddleware(map[string][]string{
+		prefix + "/device/{deviceid}/{service}": {http.MethodGet, http.MethodPatch},
+		prefix + "/device/{deviceid}/stat":      {http.MethodGet},
+	}, chain(&fanOut{
+		registry:   c.R,
+		router:     c.APIRouter,
+		bodyLimits: c.BodyLimits,
+	})))
+}
+
+// getGroup returns a group the caller is allowed to see. Other groups are
+// reported as not found.
+func getGroup(ctx context.Context, r *Registry, name string) (Group, error) {
+	g, err := r.Get(name)
+	if err != nil {
+		return Group{}, err
+	}
+	if !transaction.GetCaller(ctx).Allowed(g.Owner, g.PartnerIDs) {
+		return Group{}, ErrGroupNotFound
+	}
+	return g, nil
+}
+
+func membersEndpoint(r *Registry) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		name := request.(string)
+		if _, err := getGroup(ctx, r, name); err != nil {
+			return nil, err
+		}
+		members, err := r.Members(ctx, name)
+		return &membersResponse{Group: name, Devices: members}, err
+	}
+}
+
+func decodeGroupName(_ context.Context, r *http.Request) (interface{}, error) {
+	return mux.Vars(r)["name"], nil
+}
+
+// decodeGroupRequest decodes group creation requests and, with the name of
+// the path, group replacement ones.
+func decodeGroupRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req groupRequest
+
+	dec := json.NewDecoder(io.LimitReader(r.Body, maxRequestSize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return nil, ErrInvalidGroup
+	}
+
+	if name, ok := mux.Vars(r)["name"]; ok {
+		if req.Name != "" && req.Name != name {
+			return nil, ErrInvalidGroup
+		}
+		req.Name = name
+	}
+
+	return Group{
+		Name:     req.Name,
+		Devices:  req.Devices,
+		Selector: req.Selector,
+	}, nil
+}
+
+func encodeGroupResponse(code int) kithttp.EncodeResponseFunc {
+	return func(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+		w.Header().Set(contentTypeHeaderKey, "application/json")
+		w.Header().Set(candlelight.HeaderWPATIDKeyName, getTID(ctx))
+		w.WriteHeader(code)
+		return json.NewEncoder(w).Encode(response)
+	}
+}
+
+func getTID(ctx context.Context) string {
+	tid, _ := ctx.Value(transaction.ContextKeyRequestTID).(string)
+	return tid
+}
+
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set(contentTypeHeaderKey, "application/json")
+	w.Header().Set(candlelight.HeaderWPATIDKeyName, getTID(ctx))
+
+	var ce transaction.CodedError
+	if errors.As(err, &ce) {
+		w.WriteHeader(ce.StatusCode())
+	} else {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		//the real error is logged into our system before encodeError() is called
+		//the idea behind masking it is to not send the external API consumer internal error messages
+		err = transaction.ErrTr1d1umInternal
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": err.Error(),
+	})
+}
@@ -0,0 +1,287 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package group
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/bascule"
+	"github.com/xmidt-org/candlelight"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/tr1d1um/translation"
+	"go.uber.org/zap"
+)
+
+func newTestRouter(t *testing.T, authenticate alice.Chain) *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/device/{deviceid}/stat/watch", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// devices answer with their ID and the body they were sent, except
+	// mac:aabbccddeeff which is offline.
+	router.HandleFunc("/device/{deviceid}/{service}", func(w http.ResponseWriter, r *http.Request) {
+		deviceID := mux.Vars(r)["deviceid"]
+		if deviceID == "mac:aabbccddeeff" {
+			http.Error(w, "device not found", http.StatusNotFound)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"device": %q, "body": %q, "tid": %q}`, deviceID, body, r.Header.Get(candlelight.HeaderWPATIDKeyName))
+	})
+
+	ConfigHandler(&Options{
+		R:                           newTestRegistry(t, Config{}),
+		APIRouter:                   router,
+		Authenticate:                &authenticate,
+		Log:                         zap.NewNop(),
+		ReducedLoggingResponseCodes: func() []int { return nil },
+		BearerFingerprint:           func() transaction.FingerprintConfig { return transaction.FingerprintConfig{} },
+		BodyLimits:                  translation.BodyLimits{Default: 100},
+	})
+
+	return router
+}
+
+func serve(router http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(method, "http://localhost"+path, bytes.NewBufferString(body))
+	r.Header.Set(candlelight.HeaderWPATIDKeyName, "tid")
+	router.ServeHTTP(w, r)
+	return w
+}
+
+func TestConfigHandler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	router := newTestRouter(t, tokenChain(testToken{principal: "alice", partnerIDs: []string{"comcast"}}))
+
+	w := serve(router, http.MethodPost, "/groups", `{"name": "lab", "devices": ["mac:112233445566"]}`)
+	require.Equal(http.StatusCreated, w.Code)
+
+	w = serve(router, http.MethodPost, "/groups", `{"name": "lab", "devices": ["mac:112233445566"]}`)
+	assert.Equal(http.StatusConflict, w.Code)
+
+	w = serve(router, http.MethodPost, "/groups", `{"name": "lab", "members": ["mac:112233445566"]}`)
+	assert.Equal(http.StatusBadRequest, w.Code)
+	assert.JSONEq(`{"message": "group is invalid"}`, w.Body.String())
+
+	w = serve(router, http.MethodPut, "/groups/lab", `{"selector": {"partnerIDs": ["comcast"]}}`)
+	require.Equal(http.StatusOK, w.Code)
+
+	w = serve(router, http.MethodPut, "/groups/lab", `{"name": "other", "devices": ["mac:112233445566"]}`)
+	assert.Equal(http.StatusBadRequest, w.Code)
+
+	w = serve(router, http.MethodGet, "/groups", "")
+	require.Equal(http.StatusOK, w.Code)
+	var groups groupsResponse
+	require.NoError(json.Unmarshal(w.Body.Bytes(), &groups))
+	require.Len(groups.Groups, 1)
+	assert.Equal(&Selector{PartnerIDs: []string{"comcast"}}, groups.Groups[0].Selector)
+
+	w = serve(router, http.MethodGet, "/groups/lab", "")
+	assert.Equal(http.StatusOK, w.Code)
+
+	w = serve(router, http.MethodGet, "/groups/lab/devices", "")
+	require.Equal(http.StatusOK, w.Code)
+	assert.JSONEq(`{"group": "lab", "devices": ["mac:112233445566", "mac:aabbccddeeff"]}`, w.Body.String())
+
+	w = serve(router, http.MethodDelete, "/groups/lab", "")
+	assert.Equal(http.StatusOK, w.Code)
+
+	w = serve(router, http.MethodGet, "/groups/lab", "")
+	assert.Equal(http.StatusNotFound, w.Code)
+
+	// selectors of callers without partners would choose every partner's devices
+	w = serve(newTestRouter(t, tokenChain(testToken{principal: "bob"})), http.MethodPost, "/groups", `{"name": "lab", "selector": {"prefixes": ["mac:1122"]}}`)
+	assert.Equal(http.StatusBadRequest, w.Code)
+}
+
+func TestFanOut(t *testing.T) {
+	tests := []struct {
+		description  string
+		devices      string
+		method       string
+		path         string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			description:  "all succeeded",
+			devices:      `["mac:112233445566", "mac:112233445577"]`,
+			path:         "/device/group:lab/config",
+			body:         `{"parameters": []}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"group": "lab", "devices": {
+				"mac:112233445566": {"statusCode": 200, "body": {"device": "mac:112233445566", "body": "{\"parameters\": []}", "tid": "tid"}},
+				"mac:112233445577": {"statusCode": 200, "body": {"device": "mac:112233445577", "body": "{\"parameters\": []}", "tid": "tid"}}
+			}}`,
+		},
+		{
+			description:  "some failed",
+			devices:      `["mac:112233445566", "mac:aabbccddeeff"]`,
+			path:         "/device/group:lab/config",
+			expectedCode: http.StatusMultiStatus,
+			expectedBody: `{"group": "lab", "devices": {
+				"mac:112233445566": {"statusCode": 200, "body": {"device": "mac:112233445566", "body": "", "tid": "tid"}},
+				"mac:aabbccddeeff": {"statusCode": 404, "body": "device not found"}
+			}}`,
+		},
+		{
+			description:  "unknown group",
+			devices:      `["mac:112233445566"]`,
+			path:         "/device/group:unknown/config",
+			expectedCode: http.StatusNotFound,
+			expectedBody: `{"message": "group not found"}`,
+		},
+		{
+			description:  "body too large",
+			devices:      `["mac:112233445566"]`,
+			path:         "/device/group:lab/config",
+			body:         string(bytes.Repeat([]byte("a"), 101)),
+			expectedCode: http.StatusRequestEntityTooLarge,
+			expectedBody: `{"message": "request body too large"}`,
+		},
+		{
+			description:  "unsupported method",
+			devices:      `["mac:112233445566"]`,
+			method:       http.MethodDelete,
+			path:         "/device/group:lab/config",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: `{"message": "groups can only be targeted by GET and SET of device parameters and by device stat requests"}`,
+		},
+		{
+			description:  "unsupported route",
+			devices:      `["mac:112233445566"]`,
+			method:       http.MethodGet,
+			path:         "/device/group:lab/stat/watch",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: `{"message": "groups can only be targeted by GET and SET of device parameters and by device stat requests"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			router := newTestRouter(t, alice.New())
+			require.Equal(t, http.StatusCreated, serve(router, http.MethodPost, "/groups", `{"name": "lab", "devices": `+tc.devices+`}`).Code)
+
+			method := tc.method
+			if method == "" {
+				method = http.MethodPatch
+			}
+			w := serve(router, method, tc.path, tc.body)
+			assert.Equal(t, tc.expectedCode, w.Code)
+			assert.JSONEq(t, tc.expectedBody, w.Body.String())
+		})
+	}
+
+	t.Run("devices", func(t *testing.T) {
+		w := serve(newTestRouter(t, alice.New()), http.MethodGet, "/device/mac:112233445566/config", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"device": "mac:112233445566", "body": "", "tid": "tid"}`, w.Body.String())
+	})
+}
+
+// testToken is a token with partners.
+type testToken struct {
+	principal  string
+	partnerIDs []string
+}
+
+func (t testToken) Principal() string { return t.principal }
+
+func (t testToken) Get(key string) (interface{}, bool) {
+	if key == "partner-id" && t.partnerIDs != nil {
+		return t.partnerIDs, true
+	}
+	return nil, false
+}
+
+// tokenChain authenticates every request with token.
+func tokenChain(token bascule.Token) alice.Chain {
+	return alice.New(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(bascule.WithToken(r.Context(), token)))
+		})
+	})
+}
+
+func TestConfigHandlerScope(t *testing.T) {
+	// the test caller's token is taken from the X-Test-Principal and
+	// X-Test-Partner headers
+	router := newTestRouter(t, alice.New(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := testToken{principal: r.Header.Get("X-Test-Principal"), partnerIDs: r.Header.Values("X-Test-Partner")}
+			next.ServeHTTP(w, r.WithContext(bascule.WithToken(r.Context(), token)))
+		})
+	}))
+
+	serve := func(method, path, principal string, partnerIDs ...string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "http://localhost"+path, bytes.NewBufferString(`{"name": "lab", "devices": ["mac:112233445566"]}`))
+		r.Header.Set("X-Test-Principal", principal)
+		for _, partnerID := range partnerIDs {
+			r.Header.Add("X-Test-Partner", partnerID)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve(http.MethodPost, "/groups", "alice", "comcast")
+	require.Equal(t, http.StatusCreated, w.Code)
+	var g Group
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &g))
+	assert.Equal(t, "alice", g.Owner)
+	assert.Equal(t, []string{"comcast"}, g.PartnerIDs)
+
+	tests := []struct {
+		description string
+		principal   string
+		partnerIDs  []string
+		allowed     bool
+	}{
+		{description: "owner", principal: "alice", allowed: true},
+		{description: "same partner", principal: "bob", partnerIDs: []string{"comcast", "sky"}, allowed: true},
+		{description: "other partner", principal: "bob", partnerIDs: []string{"other"}},
+		{description: "no partner", principal: "bob"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			w := serve(http.MethodGet, "/groups", tc.principal, tc.partnerIDs...)
+			var groups groupsResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &groups))
+			assert.Equal(tc.allowed, len(groups.Groups) == 1)
+
+			expectedCode := http.StatusNotFound
+			if tc.allowed {
+				expectedCode = http.StatusOK
+			}
+			assert.Equal(expectedCode, serve(http.MethodGet, "/groups/lab", tc.principal, tc.partnerIDs...).Code)
+			assert.Equal(expectedCode, serve(http.MethodGet, "/groups/lab/devices", tc.principal, tc.partnerIDs...).Code)
+			assert.Equal(expectedCode, serve(http.MethodGet, "/device/group:lab/config", tc.principal, tc.partnerIDs...).Code)
+			if !tc.allowed {
+				assert.Equal(http.StatusNotFound, serve(http.MethodPut, "/groups/lab", tc.principal, tc.partnerIDs...).Code)
+				assert.Equal(http.StatusNotFound, serve(http.MethodDelete, "/groups/lab", tc.principal, tc.partnerIDs...).Code)
+			}
+		})
+	}
+}
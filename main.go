@@ -48,9 +48,27 @@ const (
 	hooksSchemeKey                    = "hooksScheme"
 	reducedTransactionLoggingCodesKey = "logging.reducedLoggingResponseCodes"
 	authAcquirerKey                   = "authAcquirer"
+	authPolicyKey                     = "authPolicy"
 	webhookConfigKey                  = "webhook"
 	tracingConfigKey                  = "tracing"
 	fingerprintCredsKey               = "fingerprintCreds"
+	xmidtClientTLSKey                 = "xmidtClientTLS"
+	rateLimitKey                      = "rateLimit"
+	configReloadKey                   = "configReload"
+	requestTimeoutsKey                = "requestTimeouts"
+	wrpPassthroughKey                 = "wrpPassthrough"
+	strictAcceptKey                   = "strictAccept"
+	rawWRPServicesKey                 = "rawWRPServices"
+	eventsKey                         = "events"
+	batchGetKey                       = "batchGet"
+	bodyLimitsKey                     = "bodyLimits"
+	tableSchemasKey                   = "tableSchemas"
+	scriptsKey                        = "scripts"
+	compareAndSwapKey                 = "compareAndSwap"
+	scheduleKey                       = "schedule"
+	groupsKey                         = "groups"
+	batchStatKey                      = "batchStat"
+	statWatchKey                      = "statWatch"
 )
 
 var (
@@ -62,7 +80,7 @@ var (
 
 var defaults = map[string]interface{}{
 	translationServicesKey: []string{}, // no services allowed by the default
-	targetURLKey:           "localhost:6000",
+	targetURLKey:           "http://localhost:6000",
 	netDialerTimeoutKey:    "5s",
 	clientTimeoutKey:       "50s",
 	reqTimeoutKey:          "40s",
@@ -183,8 +201,24 @@ func tr1d1um(arguments []string) (exitCode int) {
 		os.Exit(0)
 	}
 
+	err = validateConfig(v)
+	if validate, _ := f.GetBool("validate"); validate {
+		if err != nil {
+			printConfigProblems(os.Stdout, err)
+			return 1
+		}
+		fmt.Fprintln(os.Stdout, "configuration is valid")
+		return 0
+	}
+	if err != nil {
+		l.Error("invalid configuration", zap.Error(err))
+		printConfigProblems(os.Stderr, err)
+		return 1
+	}
+
 	app := fx.New(
 		arrange.LoggerFunc(l.Sugar().Infof),
+		fx.StopTimeout(drainTimeout(v)+fx.DefaultTimeout),
 		fx.Supply(l),
 		fx.Supply(v),
 		arrange.ForViper(v),
@@ -12,9 +12,17 @@ import (
 const (
 	// metric names
 	serviceConfigsRetriesCounter = "service_configs_retries"
+	authAcquirerFetchesCounter   = "auth_acquirer_fetches"
+	authAcquirerFetchDuration    = "auth_acquirer_fetch_duration_seconds"
+	configReloadsCounter         = "config_reloads"
+	configLastReloadSuccessGauge = "config_last_reload_success_timestamp_seconds"
+	drainAbortedCounter          = "drain_aborted_requests"
+	eventsSentCounter            = "events_sent"
 
 	// metric labels
-	apiLabel = "api"
+	apiLabel     = "api"
+	outcomeLabel = "outcome"
+	serviceLabel = "service"
 
 	// metric label values
 	// api
@@ -23,11 +31,54 @@ const (
 )
 
 func provideMetrics() fx.Option {
-	return touchstone.CounterVec(
-		prometheus.CounterOpts{
-			Name: serviceConfigsRetriesCounter,
-			Help: "Count of retries for xmidt service configs api calls.",
-		},
-		[]string{apiLabel}...,
+	return fx.Options(
+		touchstone.CounterVec(
+			prometheus.CounterOpts{
+				Name: serviceConfigsRetriesCounter,
+				Help: "Count of retries for xmidt service configs api calls.",
+			},
+			[]string{apiLabel}...,
+		),
+		touchstone.CounterVec(
+			prometheus.CounterOpts{
+				Name: authAcquirerFetchesCounter,
+				Help: "Count of outbound auth token fetches by outcome.",
+			},
+			[]string{outcomeLabel}...,
+		),
+		touchstone.HistogramVec(
+			prometheus.HistogramOpts{
+				Name:    authAcquirerFetchDuration,
+				Help:    "Latency of outbound auth token fetches by outcome.",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{outcomeLabel}...,
+		),
+		touchstone.CounterVec(
+			prometheus.CounterOpts{
+				Name: configReloadsCounter,
+				Help: "Count of config file reloads by outcome.",
+			},
+			[]string{outcomeLabel}...,
+		),
+		touchstone.Gauge(
+			prometheus.GaugeOpts{
+				Name: configLastReloadSuccessGauge,
+				Help: "Unix time of the last successful config load.",
+			},
+		),
+		touchstone.Counter(
+			prometheus.CounterOpts{
+				Name: drainAbortedCounter,
+				Help: "Count of in-flight XMiDT requests aborted when draining on shutdown.",
+			},
+		),
+		touchstone.CounterVec(
+			prometheus.CounterOpts{
+				Name: eventsSentCounter,
+				Help: "Count of fire-and-forget events sent to XMiDT by device service and outcome.",
+			},
+			[]string{serviceLabel, outcomeLabel}...,
+		),
 	)
 }
@@ -0,0 +1,258 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const defaultTLSReloadInterval = time.Minute
+
+var (
+	errIncompleteKeyPair = errors.New("both certificateFile and keyFile must be set")
+	errNoCACertificates  = errors.New("no PEM certificates found in caFile")
+)
+
+// outboundTLSConfig configures TLS for an outbound HTTP client, such as the
+// XMiDT client or the auth acquirer's token client.
+type outboundTLSConfig struct {
+	// CertificateFile and KeyFile are the PEM encoded client certificate and
+	// private key presented to the server for mTLS.
+	// (Optional)
+	CertificateFile string
+	KeyFile         string
+
+	// CAFile is a PEM bundle of the CAs trusted to sign server certificates.
+	// When set, it replaces the system roots.
+	// (Optional)
+	CAFile string
+
+	// ServerName overrides the name used to verify the server certificate.
+	// (Optional) Defaults to the host of the request URL.
+	ServerName string
+
+	// MinVersion is the minimum TLS version accepted, i.e. "1.2" or "1.3".
+	// (Optional) Defaults to "1.2".
+	MinVersion string
+
+	// ReloadInterval is how often the certificate files are checked for changes.
+	// (Optional) Defaults to 1m.
+	ReloadInterval time.Duration
+}
+
+// enabled reports whether any TLS settings were configured.
+func (c outboundTLSConfig) enabled() bool {
+	return c.CertificateFile != "" || c.KeyFile != "" || c.CAFile != "" ||
+		c.ServerName != "" || c.MinVersion != ""
+}
+
+// newTLSConfig builds a *tls.Config from the given configuration. Certificates
+// are loaded eagerly so that bad files fail at startup, and are then reloaded
+// from disk whenever they change. A nil config is returned when no TLS settings
+// were configured so that the transport defaults are used.
+func newTLSConfig(c outboundTLSConfig, logger *zap.Logger) (*tls.Config, error) {
+	if !c.enabled() {
+		return nil, nil
+	}
+
+	if (c.CertificateFile == "") != (c.KeyFile == "") {
+		return nil, errIncompleteKeyPair
+	}
+
+	minVersion, err := parseTLSVersion(c.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.ReloadInterval <= 0 {
+		c.ReloadInterval = defaultTLSReloadInterval
+	}
+
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	r := &certReloader{
+		config: c,
+		logger: logger,
+		now:    time.Now,
+	}
+	if err = r.load(); err != nil {
+		return nil, err
+	}
+
+	tc := &tls.Config{
+		MinVersion: minVersion,
+		ServerName: c.ServerName,
+	}
+
+	if c.CertificateFile != "" {
+		tc.GetClientCertificate = r.getClientCertificate
+	}
+
+	if c.CAFile != "" {
+		// The standard verification can't pick up a rotated CA bundle, so it is
+		// replaced by verifyConnection, which checks the full chain and host name
+		// against the most recently loaded pool.
+		tc.InsecureSkipVerify = true //nolint:gosec
+		tc.VerifyConnection = r.verifyConnection
+	}
+
+	return tc, nil
+}
+
+func parseTLSVersion(v string) (uint16, error) {
+	switch v {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported minimum TLS version '%s'", v)
+	}
+}
+
+// certReloader keeps the client certificate and CA pool in sync with the
+// files on disk.
+type certReloader struct {
+	config outboundTLSConfig
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu          sync.Mutex
+	lastChecked time.Time
+	modTimes    map[string]time.Time
+	cert        *tls.Certificate
+	pool        *x509.CertPool
+}
+
+func (r *certReloader) files() []string {
+	var files []string
+	for _, f := range []string{r.config.CertificateFile, r.config.KeyFile, r.config.CAFile} {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// load reads every configured file and swaps in the results. Nothing is
+// replaced unless all the files could be read and parsed.
+func (r *certReloader) load() error {
+	modTimes := make(map[string]time.Time)
+	for _, f := range r.files() {
+		info, err := os.Stat(f)
+		if err != nil {
+			return err
+		}
+		modTimes[f] = info.ModTime()
+	}
+
+	var (
+		cert *tls.Certificate
+		pool *x509.CertPool
+	)
+
+	if r.config.CertificateFile != "" {
+		c, err := tls.LoadX509KeyPair(r.config.CertificateFile, r.config.KeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cert = &c
+	}
+
+	if r.config.CAFile != "" {
+		pem, err := os.ReadFile(r.config.CAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read caFile: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errNoCACertificates
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.modTimes, r.cert, r.pool = modTimes, cert, pool
+	r.lastChecked = r.now()
+	return nil
+}
+
+// reloadIfChanged reloads the files if the reload interval has passed and any
+// of them has a new modification time. Failures are logged and the previously
+// loaded material stays in use.
+func (r *certReloader) reloadIfChanged() {
+	r.mu.Lock()
+	if r.now().Sub(r.lastChecked) < r.config.ReloadInterval {
+		r.mu.Unlock()
+		return
+	}
+	r.lastChecked = r.now()
+
+	changed := false
+	for _, f := range r.files() {
+		info, err := os.Stat(f)
+		if err != nil || !info.ModTime().Equal(r.modTimes[f]) {
+			changed = true
+			break
+		}
+	}
+	r.mu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	if err := r.load(); err != nil {
+		r.logger.Error("failed to reload TLS certificates, keeping previous ones", zap.Error(err))
+		return
+	}
+	r.logger.Info("reloaded TLS certificates")
+}
+
+func (r *certReloader) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.reloadIfChanged()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cert, nil
+}
+
+func (r *certReloader) verifyConnection(cs tls.ConnectionState) error {
+	r.reloadIfChanged()
+
+	r.mu.Lock()
+	pool := r.pool
+	r.mu.Unlock()
+
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("server presented no certificates")
+	}
+
+	serverName := r.config.ServerName
+	if serverName == "" {
+		serverName = cs.ServerName
+	}
+
+	opts := x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         pool,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, c := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(c)
+	}
+
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
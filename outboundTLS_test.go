@@ -0,0 +1,299 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+	kpem []byte
+}
+
+func newTestCert(t *testing.T, serial int64, parent *testCert, server bool) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "tr1d1um-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+
+	switch {
+	case parent == nil:
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+	case server:
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		tmpl.DNSNames = []string{"tr1d1um.test"}
+		tmpl.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+	default:
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	}
+
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	kder, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCert{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		kpem: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kder}),
+	}
+}
+
+func writeFile(t *testing.T, path string, data []byte, mod time.Time) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	require.NoError(t, os.Chtimes(path, mod, mod))
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, 1, nil, false)
+	client := newTestCert(t, 2, ca, false)
+
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	emptyFile := filepath.Join(dir, "empty.pem")
+	writeFile(t, caFile, ca.pem, time.Now())
+	writeFile(t, certFile, client.pem, time.Now())
+	writeFile(t, keyFile, client.kpem, time.Now())
+	writeFile(t, emptyFile, []byte("not a pem"), time.Now())
+
+	tcs := []struct {
+		name        string
+		config      outboundTLSConfig
+		expectNil   bool
+		expectErr   error
+		expectAnErr bool
+		expectMin   uint16
+	}{
+		{
+			name:      "no settings returns nil config",
+			expectNil: true,
+		},
+		{
+			name:      "certificate without key fails",
+			config:    outboundTLSConfig{CertificateFile: certFile},
+			expectErr: errIncompleteKeyPair,
+		},
+		{
+			name:        "unsupported min version fails",
+			config:      outboundTLSConfig{MinVersion: "1.1"},
+			expectAnErr: true,
+		},
+		{
+			name:        "missing ca file fails",
+			config:      outboundTLSConfig{CAFile: filepath.Join(dir, "missing.pem")},
+			expectAnErr: true,
+		},
+		{
+			name:      "ca file without certificates fails",
+			config:    outboundTLSConfig{CAFile: emptyFile},
+			expectErr: errNoCACertificates,
+		},
+		{
+			name:      "server name only uses defaults",
+			config:    outboundTLSConfig{ServerName: "tr1d1um.test"},
+			expectMin: tls.VersionTLS12,
+		},
+		{
+			name: "full config",
+			config: outboundTLSConfig{
+				CertificateFile: certFile,
+				KeyFile:         keyFile,
+				CAFile:          caFile,
+				MinVersion:      "1.3",
+			},
+			expectMin: tls.VersionTLS13,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := newTLSConfig(tc.config, zap.NewNop())
+			switch {
+			case tc.expectErr != nil:
+				assert.ErrorIs(t, err, tc.expectErr)
+				return
+			case tc.expectAnErr:
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			if tc.expectNil {
+				assert.Nil(t, actual)
+				return
+			}
+
+			require.NotNil(t, actual)
+			assert.Equal(t, tc.expectMin, actual.MinVersion)
+			assert.Equal(t, tc.config.ServerName, actual.ServerName)
+			assert.Equal(t, tc.config.CertificateFile != "", actual.GetClientCertificate != nil)
+			assert.Equal(t, tc.config.CAFile != "", actual.VerifyConnection != nil)
+		})
+	}
+}
+
+func TestOutboundMTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, 1, nil, false)
+	otherCA := newTestCert(t, 2, nil, false)
+	server := newTestCert(t, 3, ca, true)
+	client := newTestCert(t, 4, ca, false)
+
+	caFile := filepath.Join(dir, "ca.pem")
+	otherCAFile := filepath.Join(dir, "other-ca.pem")
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	writeFile(t, caFile, ca.pem, time.Now())
+	writeFile(t, otherCAFile, otherCA.pem, time.Now())
+	writeFile(t, certFile, client.pem, time.Now())
+	writeFile(t, keyFile, client.kpem, time.Now())
+
+	serverCert, err := tls.X509KeyPair(server.pem, server.kpem)
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+		MinVersion:   tls.VersionTLS12,
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	tcs := []struct {
+		name      string
+		config    outboundTLSConfig
+		expectErr bool
+	}{
+		{
+			name:   "client certificate and private CA succeed",
+			config: outboundTLSConfig{CertificateFile: certFile, KeyFile: keyFile, CAFile: caFile},
+		},
+		{
+			name:   "server name override is verified",
+			config: outboundTLSConfig{CertificateFile: certFile, KeyFile: keyFile, CAFile: caFile, ServerName: "tr1d1um.test"},
+		},
+		{
+			name:      "wrong server name fails",
+			config:    outboundTLSConfig{CertificateFile: certFile, KeyFile: keyFile, CAFile: caFile, ServerName: "other.test"},
+			expectErr: true,
+		},
+		{
+			name:      "untrusted CA fails",
+			config:    outboundTLSConfig{CertificateFile: certFile, KeyFile: keyFile, CAFile: otherCAFile},
+			expectErr: true,
+		},
+		{
+			name:      "missing client certificate fails",
+			config:    outboundTLSConfig{CAFile: caFile},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := newAuthAcquirerClient(tc.config, zap.NewNop())
+			require.NoError(t, err)
+			require.NotNil(t, client)
+
+			resp, err := client.Get(srv.URL)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		})
+	}
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, 1, nil, false)
+	first := newTestCert(t, 2, ca, false)
+	second := newTestCert(t, 3, ca, false)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	start := time.Now().Add(-time.Hour)
+	writeFile(t, certFile, first.pem, start)
+	writeFile(t, keyFile, first.kpem, start)
+
+	now := time.Now()
+	r := &certReloader{
+		config: outboundTLSConfig{CertificateFile: certFile, KeyFile: keyFile, ReloadInterval: time.Minute},
+		logger: zap.NewNop(),
+		now:    func() time.Time { return now },
+	}
+	require.NoError(t, r.load())
+
+	serial := func() int64 {
+		c, err := r.getClientCertificate(nil)
+		require.NoError(t, err)
+		leaf, err := x509.ParseCertificate(c.Certificate[0])
+		require.NoError(t, err)
+		return leaf.SerialNumber.Int64()
+	}
+	assert.Equal(t, int64(2), serial())
+
+	// rotated files aren't picked up until the reload interval passes
+	writeFile(t, certFile, second.pem, start.Add(time.Minute))
+	writeFile(t, keyFile, second.kpem, start.Add(time.Minute))
+	assert.Equal(t, int64(2), serial())
+
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, int64(3), serial())
+
+	// a broken rotation keeps the last good certificate
+	writeFile(t, certFile, []byte("garbage"), start.Add(2*time.Minute))
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, int64(3), serial())
+}
@@ -5,12 +5,14 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"time"
 
+	"github.com/go-kit/kit/metrics"
 	gokitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/viper"
@@ -45,8 +47,26 @@ type httpClientTimeout struct {
 }
 
 type authAcquirerConfig struct {
-	JWT   transaction.RemoteBearerTokenAcquirerOptions
-	Basic string
+	JWT               transaction.RemoteBearerTokenAcquirerOptions
+	ClientCredentials transaction.ClientCredentialsAcquirerOptions
+	Basic             string
+
+	// TLS configures the client used to fetch tokens.
+	TLS outboundTLSConfig
+}
+
+// authPolicyConfig chooses how outbound requests to XMiDT are authenticated
+// per API and device service.
+type authPolicyConfig struct {
+	Default  string
+	APIs     map[string]string
+	Services map[string]string
+
+	// Exchange configures the token exchange used by the "exchange" mode.
+	Exchange transaction.TokenExchangeOptions
+
+	// TLS configures the client used for token exchange.
+	TLS outboundTLSConfig
 }
 
 type provideWebhookHandlersIn struct {
@@ -70,9 +90,10 @@ type provideWebhookHandlersOut struct {
 type ServiceOptionsIn struct {
 	fx.In
 	Logger                *zap.Logger
-	XmidtClientTimeout    httpClientTimeout      `name:"xmidt_client_timeout"`
-	RequestMaxRetries     int                    `name:"requestMaxRetries"`
-	RequestRetryInterval  time.Duration          `name:"requestRetryInterval"`
+	XmidtClientTimeout    httpClientTimeout `name:"xmidt_client_timeout"`
+	XmidtClientTLS        outboundTLSConfig `name:"xmidtClientTLS"`
+	Config                *configReloader
+	Drainer               *transaction.Drainer
 	TargetURL             string                 `name:"targetURL"`
 	WRPSource             string                 `name:"WRPSource"`
 	ServiceConfigsRetries *prometheus.CounterVec `name:"service_configs_retries"`
@@ -84,13 +105,15 @@ type ServiceOptionsOut struct {
 	fx.Out
 	StatServiceOptions        *stat.ServiceOptions
 	TranslationServiceOptions *translation.ServiceOptions
+	XmidtClient               *http.Client `name:"xmidt_client"`
 }
 
-func newHTTPClient(timeouts httpClientTimeout, tracing candlelight.Tracing) *http.Client {
+func newHTTPClient(timeouts httpClientTimeout, tlsConfig *tls.Config, tracing candlelight.Tracing) *http.Client {
 	var transport http.RoundTripper = &http.Transport{
 		Dial: (&net.Dialer{
 			Timeout: timeouts.NetDialerTimeout,
 		}).Dial,
+		TLSClientConfig: tlsConfig,
 	}
 	transport = otelhttp.NewTransport(transport,
 		otelhttp.WithPropagators(tracing.Propagator()),
@@ -103,9 +126,22 @@ func newHTTPClient(timeouts httpClientTimeout, tracing candlelight.Tracing) *htt
 	}
 }
 
-func createAuthAcquirer(config authAcquirerConfig) (transaction.AuthAcquirer, error) {
+func createAuthAcquirer(config authAcquirerConfig, metrics transaction.AcquirerMetrics, logger *zap.Logger) (transaction.AuthAcquirer, error) {
 	if config.JWT.AuthURL != "" && config.JWT.Buffer != 0 && config.JWT.Timeout != 0 {
-		return &transaction.JwtAcquirer{Config: config.JWT}, nil
+		client, err := newAuthAcquirerClient(config.TLS, logger)
+		if err != nil {
+			return nil, err
+		}
+		return &transaction.JwtAcquirer{Config: config.JWT, Client: client, Metrics: metrics}, nil
+	}
+
+	cc := config.ClientCredentials
+	if cc.TokenURL != "" && cc.ClientID != "" && cc.Buffer != 0 && cc.Timeout != 0 {
+		client, err := newAuthAcquirerClient(config.TLS, logger)
+		if err != nil {
+			return nil, err
+		}
+		return &transaction.ClientCredentialsAcquirer{Config: cc, Client: client, Metrics: metrics}, nil
 	}
 
 	if config.Basic != "" {
@@ -115,6 +151,55 @@ func createAuthAcquirer(config authAcquirerConfig) (transaction.AuthAcquirer, er
 	return nil, errors.New("auth acquirer not configured properly")
 }
 
+// createOutboundAuth builds the outbound auth settings shared by the stat and
+// translation services. The acquirer, if any, provides the service identity and
+// authenticates tr1d1um to the token exchange endpoint.
+func createOutboundAuth(config authPolicyConfig, acquirer transaction.AuthAcquirer, logger *zap.Logger) (transaction.OutboundAuth, error) {
+	auth := transaction.OutboundAuth{
+		Policy: transaction.AuthPolicy{
+			Default:  config.Default,
+			APIs:     config.APIs,
+			Services: config.Services,
+		},
+		Acquirer: acquirer,
+	}
+
+	if config.Exchange.URL != "" {
+		client, err := newAuthAcquirerClient(config.TLS, logger)
+		if err != nil {
+			return auth, err
+		}
+		auth.Exchanger = &transaction.RemoteTokenExchanger{
+			Config:   config.Exchange,
+			Client:   client,
+			Acquirer: acquirer,
+		}
+	}
+
+	if err := auth.Validate(); err != nil {
+		return auth, fmt.Errorf("invalid %s: %w", authPolicyKey, err)
+	}
+
+	return auth, nil
+}
+
+// newAuthAcquirerClient returns the HTTP client used to fetch outbound auth
+// tokens. A nil client is returned when no TLS settings are configured so that
+// the acquirer falls back to http.DefaultClient.
+func newAuthAcquirerClient(c outboundTLSConfig, logger *zap.Logger) (*http.Client, error) {
+	tlsConfig, err := newTLSConfig(c, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure auth acquirer TLS: %w", err)
+	}
+	if tlsConfig == nil {
+		return nil, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport}, nil
+}
+
 func v2WebhookValidators(c ancla.Config) (webhook.Validators, error) {
 	//build validators and webhook handler for previous version that only check loopback.
 
@@ -200,6 +285,7 @@ func (s *simpleWebhookService) GetAll(ctx context.Context) ([]schema.Manifest, e
 func provideHandlers() fx.Option {
 	return fx.Options(
 		arrange.ProvideKey(authAcquirerKey, authAcquirerConfig{}),
+		arrange.ProvideKey(authPolicyKey, authPolicyConfig{}),
 		fx.Provide(
 			arrange.UnmarshalKey(webhookConfigKey, ancla.Config{}),
 			arrange.UnmarshalKey("prometheus", touchstone.Config{}),
@@ -209,26 +295,41 @@ func provideHandlers() fx.Option {
 	)
 }
 
+// retryTransactor retries requests against XMiDT using the retry policy that
+// is current when each request is made.
+func retryTransactor(logger *zap.Logger, counter metrics.Counter, policy func() (int, time.Duration), do func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+	return func(r *http.Request) (*http.Response, error) {
+		retries, interval := policy()
+		return xhttp.RetryTransactor( //nolint:bodyclose
+			xhttp.RetryOptions{
+				Logger:   logger,
+				Retries:  retries,
+				Interval: interval,
+				Counter:  counter,
+			},
+			do)(r)
+	}
+}
+
 func provideServiceOptions(in ServiceOptionsIn) (ServiceOptionsOut, error) {
 	var errs error
 
-	xmidtHTTPClient := newHTTPClient(in.XmidtClientTimeout, in.Tracing)
+	xmidtTLSConfig, err := newTLSConfig(in.XmidtClientTLS, in.Logger)
+	if err != nil {
+		return ServiceOptionsOut{}, fmt.Errorf("failed to configure XMiDT client TLS: %w", err)
+	}
+
+	xmidtHTTPClient := newHTTPClient(in.XmidtClientTimeout, xmidtTLSConfig, in.Tracing)
 	stat_retries_counter, err := in.ServiceConfigsRetries.CurryWith(prometheus.Labels{apiLabel: stat_api})
 	errs = errors.Join(errs, err)
 	// Stat Service configs
 	statOptions := &stat.ServiceOptions{
-		HTTPTransactor: transaction.New(
+		HTTPTransactor: in.Drainer.Track(transaction.New(
 			&transaction.Options{
-				Do: xhttp.RetryTransactor( //nolint:bodyclose
-					xhttp.RetryOptions{
-						Logger:   in.Logger,
-						Retries:  in.RequestMaxRetries,
-						Interval: in.RequestRetryInterval,
-						Counter:  gokitprometheus.NewCounter(stat_retries_counter),
-					},
-					xmidtHTTPClient.Do),
+				Do: retryTransactor(in.Logger, gokitprometheus.NewCounter(stat_retries_counter),
+					in.Config.retryPolicy, xmidtHTTPClient.Do),
 				RequestTimeout: in.XmidtClientTimeout.RequestTimeout,
-			}),
+			})),
 		XmidtStatURL: fmt.Sprintf("%s/device/${device}/stat", in.TargetURL),
 	}
 
@@ -238,22 +339,17 @@ func provideServiceOptions(in ServiceOptionsIn) (ServiceOptionsOut, error) {
 	translationOptions := &translation.ServiceOptions{
 		XmidtWrpURL: fmt.Sprintf("%s/device", in.TargetURL),
 		WRPSource:   in.WRPSource,
-		T: transaction.New(
+		T: in.Drainer.Track(transaction.New(
 			&transaction.Options{
 				RequestTimeout: in.XmidtClientTimeout.RequestTimeout,
-				Do: xhttp.RetryTransactor( //nolint:bodyclose
-					xhttp.RetryOptions{
-						Logger:   in.Logger,
-						Retries:  in.RequestMaxRetries,
-						Interval: in.RequestRetryInterval,
-						Counter:  gokitprometheus.NewCounter(device_retries_counter),
-					},
-					xmidtHTTPClient.Do),
-			}),
+				Do: retryTransactor(in.Logger, gokitprometheus.NewCounter(device_retries_counter),
+					in.Config.retryPolicy, xmidtHTTPClient.Do),
+			})),
 	}
 
 	return ServiceOptionsOut{
 		StatServiceOptions:        statOptions,
 		TranslationServiceOptions: translationOptions,
+		XmidtClient:               xmidtHTTPClient,
 	}, errs
 }
@@ -41,6 +41,19 @@ func TestCreateAuthAcquirer(t *testing.T) {
 			},
 			expectType: "jwt",
 		},
+		{
+			name: "returns client credentials acquirer when full OAuth2 config is set",
+			config: authAcquirerConfig{
+				ClientCredentials: transaction.ClientCredentialsAcquirerOptions{
+					TokenURL: "https://auth.example/oauth2/token",
+					ClientID: "tr1d1um",
+					Timeout:  3 * time.Second,
+					Buffer:   2 * time.Second,
+				},
+				Basic: "Basic dXNlcjpwYXNz",
+			},
+			expectType: "clientCredentials",
+		},
 		{
 			name: "falls back to basic acquirer when JWT config is incomplete",
 			config: authAcquirerConfig{
@@ -61,7 +74,7 @@ func TestCreateAuthAcquirer(t *testing.T) {
 
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
-			acquirer, err := createAuthAcquirer(tc.config)
+			acquirer, err := createAuthAcquirer(tc.config, transaction.AcquirerMetrics{}, zap.NewNop())
 			if tc.expectError {
 				require.Error(t, err)
 				assert.Nil(t, acquirer)
@@ -76,6 +89,10 @@ func TestCreateAuthAcquirer(t *testing.T) {
 				jwtAcquirer, ok := acquirer.(*transaction.JwtAcquirer)
 				require.True(t, ok)
 				assert.Equal(t, tc.config.JWT, jwtAcquirer.Config)
+			case "clientCredentials":
+				ccAcquirer, ok := acquirer.(*transaction.ClientCredentialsAcquirer)
+				require.True(t, ok)
+				assert.Equal(t, tc.config.ClientCredentials, ccAcquirer.Config)
 			case "basic":
 				basicAcquirer, ok := acquirer.(*transaction.BasicAcquirer)
 				require.True(t, ok)
@@ -87,6 +104,59 @@ func TestCreateAuthAcquirer(t *testing.T) {
 	}
 }
 
+func TestCreateOutboundAuth(t *testing.T) {
+	tcs := []struct {
+		name            string
+		config          authPolicyConfig
+		acquirer        transaction.AuthAcquirer
+		expectExchanger bool
+		expectError     bool
+	}{
+		{
+			name: "passthrough without acquirer",
+		},
+		{
+			name: "exchange with exchange URL",
+			config: authPolicyConfig{
+				Default:  transaction.AuthModeService,
+				Services: map[string]string{"config": transaction.AuthModeExchange},
+				Exchange: transaction.TokenExchangeOptions{URL: "https://auth.example/exchange"},
+			},
+			acquirer:        &transaction.BasicAcquirer{Token: "Basic dXNlcjpwYXNz"},
+			expectExchanger: true,
+		},
+		{
+			name: "exchange without exchange URL",
+			config: authPolicyConfig{
+				APIs: map[string]string{transaction.APIDevice: transaction.AuthModeExchange},
+			},
+			expectError: true,
+		},
+		{
+			name: "service without acquirer",
+			config: authPolicyConfig{
+				Default: transaction.AuthModeService,
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			auth, err := createOutboundAuth(tc.config, tc.acquirer, zap.NewNop())
+			if tc.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), authPolicyKey)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.config.Default, auth.Policy.Default)
+			assert.Equal(t, tc.expectExchanger, auth.Exchanger != nil)
+		})
+	}
+}
+
 func TestBuildWebhookValidators(t *testing.T) {
 	tcs := []struct {
 		name             string
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var errRateLimited = errors.New("rate limit exceeded")
+
+// rateLimitConfig limits the rate of API requests tr1d1um accepts.
+type rateLimitConfig struct {
+	// RequestsPerSecond is the sustained request rate.
+	// (Optional) By default, requests are not rate limited.
+	RequestsPerSecond float64
+
+	// Burst is the number of requests that may be served at once.
+	// (Optional) Defaults to RequestsPerSecond, rounded up.
+	Burst int
+}
+
+func (c rateLimitConfig) validate() error {
+	var errs error
+	if c.RequestsPerSecond < 0 {
+		errs = errors.Join(errs, fmt.Errorf("%s.requestsPerSecond: must not be negative", rateLimitKey))
+	}
+	if c.Burst < 0 {
+		errs = errors.Join(errs, fmt.Errorf("%s.burst: must not be negative", rateLimitKey))
+	}
+	return errs
+}
+
+func (c rateLimitConfig) burst() float64 {
+	if c.Burst > 0 {
+		return float64(c.Burst)
+	}
+	return math.Ceil(c.RequestsPerSecond)
+}
+
+// rateLimiter is a token bucket whose limits are read on every request so
+// they may change at runtime. The bucket is refilled when they do.
+type rateLimiter struct {
+	config func() rateLimitConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	current rateLimitConfig
+	tokens  float64
+	last    time.Time
+}
+
+func newRateLimiter(config func() rateLimitConfig) *rateLimiter {
+	return &rateLimiter{
+		config: config,
+		now:    time.Now,
+	}
+}
+
+func (l *rateLimiter) allow() bool {
+	c := l.config()
+	if c.RequestsPerSecond <= 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if c != l.current {
+		l.current = c
+		l.tokens = c.burst()
+		l.last = now
+	}
+
+	l.tokens = math.Min(c.burst(), l.tokens+now.Sub(l.last).Seconds()*c.RequestsPerSecond)
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+
+	l.tokens--
+	return true
+}
+
+// Then is an Alice-style constructor that rejects requests over the rate
+// limit with a 429.
+func (l *rateLimiter) Then(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.allow() {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"message": errRateLimited.Error(),
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
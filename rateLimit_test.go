@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	config := rateLimitConfig{RequestsPerSecond: 2, Burst: 3}
+	now := time.Now()
+	l := newRateLimiter(func() rateLimitConfig { return config })
+	l.now = func() time.Time { return now }
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := l.Then(next)
+	serve := func() int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rec.Code
+	}
+
+	// the burst is served, then requests are rejected
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, serve())
+	}
+	assert.Equal(t, http.StatusTooManyRequests, serve())
+
+	// tokens are refilled at the sustained rate
+	now = now.Add(500 * time.Millisecond)
+	assert.Equal(t, http.StatusOK, serve())
+	assert.Equal(t, http.StatusTooManyRequests, serve())
+
+	// a new config refills the bucket
+	config = rateLimitConfig{RequestsPerSecond: 1}
+	assert.Equal(t, http.StatusOK, serve())
+	assert.Equal(t, http.StatusTooManyRequests, serve())
+
+	// no rate limit
+	config = rateLimitConfig{}
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, http.StatusOK, serve())
+	}
+}
+
+func TestRateLimiterResponse(t *testing.T) {
+	l := newRateLimiter(func() rateLimitConfig { return rateLimitConfig{RequestsPerSecond: 0.5} })
+	handler := l.Then(http.NotFoundHandler())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"message":"rate limit exceeded"}`, rec.Body.String())
+}
@@ -0,0 +1,305 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/viper"
+	"github.com/xmidt-org/clortho"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/tr1d1um/translation"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	readinessKey = "readiness"
+
+	checkPassed   = "pass"
+	checkFailed   = "fail"
+	checkPending  = "pending"
+	checkDraining = "draining"
+
+	defaultReadinessInterval = 10 * time.Second
+	defaultReadinessTimeout  = 5 * time.Second
+)
+
+// readinessConfig configures the dependency checks served at /ready.
+type readinessConfig struct {
+	// Interval is how often the checks are run in the background.
+	// (Optional) Defaults to 10s.
+	Interval time.Duration
+
+	// Timeout is how long each check may take.
+	// (Optional) Defaults to 5s.
+	Timeout time.Duration
+}
+
+// readinessCheck is a single dependency check. A nil error means the
+// dependency is usable.
+type readinessCheck struct {
+	Name  string
+	Check func(context.Context) error
+}
+
+// checkResult is the outcome of a readinessCheck.
+type checkResult struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// readinessReport is the latest outcome of every readinessCheck.
+type readinessReport struct {
+	Status    string                 `json:"status"`
+	CheckedAt time.Time              `json:"checkedAt,omitzero"`
+	Checks    map[string]checkResult `json:"checks"`
+}
+
+// readiness runs its checks in the background and serves the cached report,
+// so that probes don't put load on the dependencies.
+type readiness struct {
+	checks   []readinessCheck
+	interval time.Duration
+	timeout  time.Duration
+	logger   *zap.Logger
+
+	// draining reports whether tr1d1um is shutting down, which makes it
+	// not ready whatever the checks say.
+	draining func() bool
+
+	mu     sync.RWMutex
+	report readinessReport
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+type readinessIn struct {
+	fx.In
+	Lifecycle          fx.Lifecycle
+	V                  *viper.Viper
+	Logger             *zap.Logger
+	Config             readinessConfig `name:"readiness"`
+	Router             *mux.Router     `name:"server_health"`
+	XmidtClient        *http.Client    `name:"xmidt_client"`
+	TargetURL          string          `name:"targetURL"`
+	KeyResolver        clortho.Resolver
+	TranslationOptions *translation.ServiceOptions
+	Drainer            *transaction.Drainer
+}
+
+func newReadiness(c readinessConfig, logger *zap.Logger, checks ...readinessCheck) *readiness {
+	if c.Interval <= 0 {
+		c.Interval = defaultReadinessInterval
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultReadinessTimeout
+	}
+
+	r := &readiness{
+		checks:   checks,
+		interval: c.Interval,
+		timeout:  c.Timeout,
+		logger:   logger,
+		report: readinessReport{
+			Status: checkPending,
+			Checks: make(map[string]checkResult, len(checks)),
+		},
+	}
+	for _, c := range checks {
+		r.report.Checks[c.Name] = checkResult{Status: checkPending}
+	}
+
+	return r
+}
+
+// refresh runs every check concurrently and replaces the report.
+func (r *readiness) refresh(ctx context.Context) {
+	results := make([]checkResult, len(r.checks))
+
+	var wg sync.WaitGroup
+	for i, c := range r.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = r.run(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	report := readinessReport{
+		Status:    checkPassed,
+		CheckedAt: time.Now(),
+		Checks:    make(map[string]checkResult, len(r.checks)),
+	}
+	for i, c := range r.checks {
+		report.Checks[c.Name] = results[i]
+		if results[i].Status != checkPassed {
+			report.Status = checkFailed
+		}
+	}
+
+	r.mu.Lock()
+	previous := r.report.Status
+	r.report = report
+	r.mu.Unlock()
+
+	if previous != report.Status {
+		r.logger.Info("readiness changed", zap.String("status", report.Status), zap.Any("checks", report.Checks))
+	}
+}
+
+func (r *readiness) run(ctx context.Context, c readinessCheck) checkResult {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.Check(ctx)
+	result := checkResult{
+		Status:  checkPassed,
+		Latency: time.Since(start).String(),
+	}
+	if err != nil {
+		result.Status = checkFailed
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// Report returns the latest readiness report.
+func (r *readiness) Report() readinessReport {
+	r.mu.RLock()
+	report := r.report
+	r.mu.RUnlock()
+
+	if r.draining != nil && r.draining() {
+		report.Status = checkDraining
+	}
+	return report
+}
+
+func (r *readiness) start() {
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			select {
+			case <-r.stop:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			r.refresh(ctx)
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop ends the background checks.
+func (r *readiness) Stop() {
+	if r.stop != nil {
+		close(r.stop)
+		<-r.done
+		r.stop = nil
+	}
+}
+
+// ServeHTTP writes the latest report as JSON, with a 503 unless every check
+// passed.
+func (r *readiness) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	report := r.Report()
+	w.Header().Set("Content-Type", "application/json")
+	if report.Status != checkPassed {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// xmidtCheck passes when targetURL responds to HTTP requests without a server
+// error. Other error codes, such as a 404 for the base URL, are expected.
+func xmidtCheck(client *http.Client, targetURL string) readinessCheck {
+	return readinessCheck{
+		Name: "xmidt",
+		Check: func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+			if err != nil {
+				return err
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				return err
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode >= http.StatusInternalServerError {
+				return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+			}
+			return nil
+		},
+	}
+}
+
+// keyResolverCheck passes when the key resolver can resolve the default key.
+func keyResolverCheck(resolver clortho.Resolver) readinessCheck {
+	return readinessCheck{
+		Name: "keyResolver",
+		Check: func(ctx context.Context) error {
+			_, err := resolver.Resolve(ctx, DefaultKeyID)
+			return err
+		},
+	}
+}
+
+func handleReadinessRoutes(in readinessIn) {
+	checks := []readinessCheck{
+		xmidtCheck(in.XmidtClient, in.TargetURL),
+		keyResolverCheck(in.KeyResolver),
+	}
+
+	// The acquirer is set up by handlePrimaryEndpoint, so it's only looked up
+	// when the check runs.
+	if in.V.IsSet(authAcquirerKey) {
+		checks = append(checks, readinessCheck{
+			Name: "authAcquirer",
+			Check: func(ctx context.Context) error {
+				acquirer := in.TranslationOptions.AuthAcquirer
+				if acquirer == nil {
+					return errors.New("auth acquirer not configured")
+				}
+				_, err := acquirer.Acquire(ctx)
+				return err
+			},
+		})
+	}
+
+	r := newReadiness(in.Config, in.Logger, checks...)
+	r.draining = in.Drainer.Draining
+	in.Lifecycle.Append(fx.StartStopHook(r.start, r.Stop))
+	if in.Router != nil {
+		in.Router.Handle("/ready", r).Methods(http.MethodGet)
+	}
+}
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestReadiness(t *testing.T) {
+	var keyErr error
+	r := newReadiness(readinessConfig{}, zap.NewNop(),
+		readinessCheck{Name: "xmidt", Check: func(context.Context) error { return nil }},
+		readinessCheck{Name: "keyResolver", Check: func(context.Context) error { return keyErr }},
+	)
+	assert.Equal(t, defaultReadinessInterval, r.interval)
+	assert.Equal(t, defaultReadinessTimeout, r.timeout)
+
+	serve := func() (int, readinessReport) {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var report readinessReport
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		return rec.Code, report
+	}
+
+	// not ready until the checks have run
+	code, report := serve()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, checkPending, report.Status)
+	assert.Equal(t, checkPending, report.Checks["keyResolver"].Status)
+
+	r.refresh(context.Background())
+	code, report = serve()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, checkPassed, report.Status)
+	assert.Equal(t, checkPassed, report.Checks["xmidt"].Status)
+	assert.NotEmpty(t, report.Checks["xmidt"].Latency)
+	assert.False(t, report.CheckedAt.IsZero())
+
+	keyErr = errors.New("key not found")
+	r.refresh(context.Background())
+	code, report = serve()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, checkFailed, report.Status)
+	assert.Equal(t, checkPassed, report.Checks["xmidt"].Status)
+	assert.Equal(t, checkResult{Status: checkFailed, Latency: report.Checks["keyResolver"].Latency, Error: "key not found"}, report.Checks["keyResolver"])
+
+	// a draining instance is never ready
+	keyErr = nil
+	r.refresh(context.Background())
+	r.draining = func() bool { return true }
+	code, report = serve()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, checkDraining, report.Status)
+	assert.Equal(t, checkPassed, report.Checks["keyResolver"].Status)
+}
+
+func TestReadinessTimeout(t *testing.T) {
+	r := newReadiness(readinessConfig{Timeout: 10 * time.Millisecond}, zap.NewNop(),
+		readinessCheck{Name: "slow", Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+	)
+
+	r.refresh(context.Background())
+	report := r.Report()
+	assert.Equal(t, checkFailed, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"].Error)
+}
+
+func TestReadinessBackground(t *testing.T) {
+	var runs atomic.Int32
+	r := newReadiness(readinessConfig{Interval: 10 * time.Millisecond}, zap.NewNop(),
+		readinessCheck{Name: "count", Check: func(context.Context) error {
+			runs.Add(1)
+			return nil
+		}},
+	)
+
+	r.start()
+	assert.Eventually(t, func() bool { return runs.Load() > 2 }, time.Second, 5*time.Millisecond)
+	r.Stop()
+	assert.Equal(t, checkPassed, r.Report().Status)
+}
+
+func TestXmidtCheck(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusNotFound)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	check := xmidtCheck(server.Client(), server.URL)
+
+	assert.NoError(t, check.Check(context.Background()))
+
+	status.Store(http.StatusBadGateway)
+	assert.ErrorContains(t, check.Check(context.Background()), "502")
+
+	server.Close()
+	assert.Error(t, check.Check(context.Background()))
+}
+
+func TestKeyResolverCheck(t *testing.T) {
+	resolver := &mockResolver{key: &mockClorthoKey{keyID: DefaultKeyID}}
+	check := keyResolverCheck(resolver)
+
+	assert.NoError(t, check.Check(context.Background()))
+	assert.Equal(t, DefaultKeyID, resolver.lastKeyID)
+
+	resolver.resolveErr = errors.New("key not found")
+	assert.Error(t, check.Check(context.Background()))
+}
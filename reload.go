@@ -0,0 +1,357 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/viper"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	reloadSuccess = "success"
+	reloadFailure = "failure"
+)
+
+// runtimeConfig holds the settings that are reloaded from the config file
+// without a restart. Every other setting requires a restart to change.
+type runtimeConfig struct {
+	SupportedServices           []string
+	ReducedLoggingResponseCodes []int
+	BearerFingerprint           transaction.FingerprintConfig
+	RateLimit                   rateLimitConfig
+	RequestMaxRetries           int
+	RequestRetryInterval        time.Duration
+}
+
+// configReloadConfig configures how the config file is watched for changes.
+type configReloadConfig struct {
+	// Interval is how often the config file is checked for changes.
+	// (Optional) By default, the config file is not reloaded.
+	Interval time.Duration
+}
+
+// reloadStatus reports the outcome of the latest config reloads.
+type reloadStatus struct {
+	File        string    `json:"file"`
+	Generation  int       `json:"generation"`
+	LastAttempt time.Time `json:"lastAttempt,omitzero"`
+	LastSuccess time.Time `json:"lastSuccess"`
+	LastError   string    `json:"lastError,omitempty"`
+}
+
+// loadRuntimeConfig reads the reloadable settings from v and validates them.
+func loadRuntimeConfig(v *viper.Viper) (runtimeConfig, error) {
+	var (
+		c    runtimeConfig
+		errs error
+	)
+
+	unmarshal := func(key string, target interface{}) {
+		if err := v.UnmarshalKey(key, target); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+
+	unmarshal(translationServicesKey, &c.SupportedServices)
+	unmarshal(reducedTransactionLoggingCodesKey, &c.ReducedLoggingResponseCodes)
+	unmarshal(fingerprintCredsKey, &c.BearerFingerprint)
+	unmarshal(rateLimitKey, &c.RateLimit)
+	unmarshal(reqMaxRetriesKey, &c.RequestMaxRetries)
+	unmarshal(reqRetryIntervalKey, &c.RequestRetryInterval)
+	if errs != nil {
+		return c, errs
+	}
+
+	return c, c.validate()
+}
+
+func (c runtimeConfig) validate() error {
+	var errs error
+
+	if len(c.SupportedServices) == 0 {
+		errs = errors.Join(errs, fmt.Errorf("%s: at least one service must be listed", translationServicesKey))
+	}
+	for i, s := range c.SupportedServices {
+		if strings.TrimSpace(s) == "" {
+			errs = errors.Join(errs, fmt.Errorf("%s[%d]: service name is empty", translationServicesKey, i))
+		}
+	}
+	for i, code := range c.ReducedLoggingResponseCodes {
+		if code < 100 || code > 599 {
+			errs = errors.Join(errs, fmt.Errorf("%s[%d]: %d is not an HTTP status code", reducedTransactionLoggingCodesKey, i, code))
+		}
+	}
+	if c.BearerFingerprint.LastNDigits < 0 {
+		errs = errors.Join(errs, fmt.Errorf("%s.lastNDigits: must not be negative", fingerprintCredsKey))
+	}
+	if c.RequestMaxRetries < 0 {
+		errs = errors.Join(errs, fmt.Errorf("%s: must not be negative", reqMaxRetriesKey))
+	}
+	if c.RequestRetryInterval < 0 {
+		errs = errors.Join(errs, fmt.Errorf("%s: must not be negative", reqRetryIntervalKey))
+	}
+
+	return errors.Join(errs, c.RateLimit.validate())
+}
+
+// readRuntimeConfig reads the reloadable settings from a config file.
+func readRuntimeConfig(file string) (runtimeConfig, error) {
+	v := newViper()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		return runtimeConfig{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	return loadRuntimeConfig(v)
+}
+
+// configReloader keeps the current runtimeConfig, reloading it whenever the
+// config file changes. An invalid config file is rejected and the previous
+// config stays active.
+type configReloader struct {
+	file     string
+	interval time.Duration
+	logger   *zap.Logger
+	read     func(string) (runtimeConfig, error)
+
+	reloads     *prometheus.CounterVec
+	lastSuccess prometheus.Gauge
+
+	current atomic.Pointer[runtimeConfig]
+
+	mu      sync.Mutex
+	modTime time.Time
+	status  reloadStatus
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+type configReloaderIn struct {
+	fx.In
+	Lifecycle   fx.Lifecycle
+	V           *viper.Viper
+	Logger      *zap.Logger
+	Config      configReloadConfig     `name:"configReload"`
+	Reloads     *prometheus.CounterVec `name:"config_reloads"`
+	LastSuccess prometheus.Gauge       `name:"config_last_reload_success_timestamp_seconds"`
+}
+
+type configReloadRoutesIn struct {
+	fx.In
+	Router   *mux.Router `name:"server_health"`
+	Reloader *configReloader
+}
+
+func provideConfigReloader(in configReloaderIn) (*configReloader, error) {
+	c, err := loadRuntimeConfig(in.V)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	r := newConfigReloader(in.V.ConfigFileUsed(), in.Config.Interval, c, in.Logger)
+	r.reloads = in.Reloads
+	r.lastSuccess = in.LastSuccess
+	if r.lastSuccess != nil {
+		r.lastSuccess.SetToCurrentTime()
+	}
+
+	if r.file != "" && r.interval > 0 {
+		in.Lifecycle.Append(fx.StartStopHook(r.start, r.Stop))
+		in.Logger.Info("config reloading enabled", zap.String("file", r.file), zap.Duration("interval", r.interval))
+	}
+
+	return r, nil
+}
+
+func newConfigReloader(file string, interval time.Duration, c runtimeConfig, logger *zap.Logger) *configReloader {
+	r := &configReloader{
+		file:     file,
+		interval: interval,
+		logger:   logger,
+		read:     readRuntimeConfig,
+		status: reloadStatus{
+			File:        file,
+			LastSuccess: time.Now(),
+		},
+	}
+	r.current.Store(&c)
+	if fi, err := os.Stat(file); err == nil {
+		r.modTime = fi.ModTime()
+	}
+
+	return r
+}
+
+// Current returns the active runtimeConfig. It must not be modified.
+func (r *configReloader) Current() *runtimeConfig {
+	return r.current.Load()
+}
+
+func (r *configReloader) supportedServices() []string {
+	return r.Current().SupportedServices
+}
+
+func (r *configReloader) reducedLoggingResponseCodes() []int {
+	return r.Current().ReducedLoggingResponseCodes
+}
+
+func (r *configReloader) bearerFingerprint() transaction.FingerprintConfig {
+	return r.Current().BearerFingerprint
+}
+
+func (r *configReloader) rateLimit() rateLimitConfig {
+	return r.Current().RateLimit
+}
+
+func (r *configReloader) retryPolicy() (int, time.Duration) {
+	c := r.Current()
+	return c.RequestMaxRetries, c.RequestRetryInterval
+}
+
+// Status returns the outcome of the latest reloads.
+func (r *configReloader) Status() reloadStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+// check reloads the config file if it changed since the last check.
+func (r *configReloader) check() {
+	fi, err := os.Stat(r.file)
+	if err != nil {
+		r.logger.Error("failed to check config file", zap.String("file", r.file), zap.Error(err))
+		return
+	}
+
+	r.mu.Lock()
+	changed := !fi.ModTime().Equal(r.modTime)
+	r.modTime = fi.ModTime()
+	r.mu.Unlock()
+
+	if changed {
+		_ = r.reload()
+	}
+}
+
+// reload reads the config file and, if it is valid, makes it the active config.
+func (r *configReloader) reload() error {
+	c, err := r.read(r.file)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.status.LastAttempt = time.Now()
+	if err != nil {
+		r.status.LastError = err.Error()
+		r.observe(reloadFailure)
+		r.logger.Error("rejected config reload, keeping the previous config", zap.String("file", r.file), zap.Error(err))
+		return err
+	}
+
+	previous := r.current.Swap(&c)
+	r.status.Generation++
+	r.status.LastSuccess = r.status.LastAttempt
+	r.status.LastError = ""
+	r.observe(reloadSuccess)
+	if r.lastSuccess != nil {
+		r.lastSuccess.SetToCurrentTime()
+	}
+
+	r.logger.Info("reloaded config",
+		zap.String("file", r.file),
+		zap.Int("generation", r.status.Generation),
+		zap.Strings("changed", c.changed(previous)),
+	)
+	return nil
+}
+
+func (r *configReloader) observe(outcome string) {
+	if r.reloads != nil {
+		r.reloads.With(prometheus.Labels{outcomeLabel: outcome}).Inc()
+	}
+}
+
+func (r *configReloader) start() {
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.check()
+			}
+		}
+	}()
+}
+
+// Stop ends watching the config file.
+func (r *configReloader) Stop() {
+	if r.stop != nil {
+		close(r.stop)
+		<-r.done
+		r.stop = nil
+	}
+}
+
+// ServeHTTP writes the reload status as JSON.
+func (r *configReloader) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(r.Status())
+}
+
+// changed returns the keys whose values differ from previous.
+func (c runtimeConfig) changed(previous *runtimeConfig) []string {
+	var keys []string
+	if previous == nil {
+		return keys
+	}
+
+	if !slices.Equal(c.SupportedServices, previous.SupportedServices) {
+		keys = append(keys, translationServicesKey)
+	}
+	if !slices.Equal(c.ReducedLoggingResponseCodes, previous.ReducedLoggingResponseCodes) {
+		keys = append(keys, reducedTransactionLoggingCodesKey)
+	}
+	if c.BearerFingerprint != previous.BearerFingerprint {
+		keys = append(keys, fingerprintCredsKey)
+	}
+	if c.RateLimit != previous.RateLimit {
+		keys = append(keys, rateLimitKey)
+	}
+	if c.RequestMaxRetries != previous.RequestMaxRetries {
+		keys = append(keys, reqMaxRetriesKey)
+	}
+	if c.RequestRetryInterval != previous.RequestRetryInterval {
+		keys = append(keys, reqRetryIntervalKey)
+	}
+
+	return keys
+}
+
+func handleConfigReloadRoutes(in configReloadRoutesIn) {
+	if in.Router != nil {
+		in.Router.Handle("/config/reload", in.Reloader).Methods(http.MethodGet)
+	}
+}
@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"go.uber.org/zap"
+)
+
+func TestLoadRuntimeConfig(t *testing.T) {
+	tcs := []struct {
+		name      string
+		config    string
+		expected  runtimeConfig
+		expectErr []string
+	}{
+		{
+			name:   "defaults",
+			config: `supportedServices: ["config"]`,
+			expected: runtimeConfig{
+				SupportedServices:    []string{"config"},
+				RequestMaxRetries:    2,
+				RequestRetryInterval: 2 * time.Second,
+			},
+		},
+		{
+			name: "all settings",
+			config: `
+supportedServices: ["config", "iot"]
+logging:
+  reducedLoggingResponseCodes: [200, 504]
+fingerprintCreds:
+  sha256: true
+  lastNDigits: 4
+rateLimit:
+  requestsPerSecond: 2.5
+  burst: 5
+requestMaxRetries: 3
+requestRetryInterval: "1s"
+`,
+			expected: runtimeConfig{
+				SupportedServices:           []string{"config", "iot"},
+				ReducedLoggingResponseCodes: []int{200, 504},
+				BearerFingerprint:           transaction.FingerprintConfig{SHA256: true, LastNDigits: 4},
+				RateLimit:                   rateLimitConfig{RequestsPerSecond: 2.5, Burst: 5},
+				RequestMaxRetries:           3,
+				RequestRetryInterval:        time.Second,
+			},
+		},
+		{
+			name: "invalid settings",
+			config: `
+supportedServices: ["config", " "]
+logging:
+  reducedLoggingResponseCodes: [200, 42]
+fingerprintCreds:
+  lastNDigits: -1
+rateLimit:
+  requestsPerSecond: -1
+  burst: -1
+requestMaxRetries: -1
+requestRetryInterval: "-1s"
+`,
+			expectErr: []string{
+				"supportedServices[1]",
+				"logging.reducedLoggingResponseCodes[1]",
+				"fingerprintCreds.lastNDigits",
+				"rateLimit.requestsPerSecond",
+				"rateLimit.burst",
+				"requestMaxRetries",
+				"requestRetryInterval",
+			},
+		},
+		{
+			name:      "no services",
+			expectErr: []string{"supportedServices: at least one service"},
+		},
+		{
+			name:      "wrong type",
+			config:    `requestRetryInterval: "soon"`,
+			expectErr: []string{"requestRetryInterval"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			v := viper.New()
+			for k, va := range defaults {
+				v.SetDefault(k, va)
+			}
+			v.SetConfigType("yaml")
+			require.NoError(t, v.ReadConfig(strings.NewReader(tc.config)))
+
+			actual, err := loadRuntimeConfig(v)
+			if len(tc.expectErr) > 0 {
+				require.Error(t, err)
+				for _, e := range tc.expectErr {
+					assert.Contains(t, err.Error(), e)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestConfigReloader(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tr1d1um.yaml")
+	write := func(content string, modTime time.Time) {
+		require.NoError(t, os.WriteFile(file, []byte(content), 0600))
+		require.NoError(t, os.Chtimes(file, modTime, modTime))
+	}
+
+	start := time.Now().Add(-time.Hour)
+	write(`supportedServices: ["config"]`, start)
+	initial, err := readRuntimeConfig(file)
+	require.NoError(t, err)
+
+	reloads := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "reloads"}, []string{outcomeLabel})
+	lastSuccess := prometheus.NewGauge(prometheus.GaugeOpts{Name: "last_success"})
+	r := newConfigReloader(file, time.Minute, initial, zap.NewNop())
+	r.reloads = reloads
+	r.lastSuccess = lastSuccess
+
+	// an unchanged file is not reloaded
+	r.check()
+	assert.Equal(t, 0, r.Status().Generation)
+
+	write(`
+supportedServices: ["config", "iot"]
+requestMaxRetries: 5
+`, start.Add(time.Minute))
+	r.check()
+	assert.Equal(t, []string{"config", "iot"}, r.supportedServices())
+	retries, _ := r.retryPolicy()
+	assert.Equal(t, 5, retries)
+	assert.Equal(t, 1, r.Status().Generation)
+	assert.Empty(t, r.Status().LastError)
+	assert.Equal(t, 1.0, testutil.ToFloat64(reloads.WithLabelValues(reloadSuccess)))
+	assert.NotZero(t, testutil.ToFloat64(lastSuccess))
+
+	// an invalid file is rejected and the previous config stays active
+	write(`requestMaxRetries: -3`, start.Add(2*time.Minute))
+	r.check()
+	retries, _ = r.retryPolicy()
+	assert.Equal(t, 5, retries)
+	assert.Equal(t, []string{"config", "iot"}, r.supportedServices())
+	status := r.Status()
+	assert.Equal(t, 1, status.Generation)
+	assert.Contains(t, status.LastError, "requestMaxRetries")
+	assert.True(t, status.LastAttempt.After(status.LastSuccess) || status.LastAttempt.Equal(status.LastSuccess))
+	assert.Equal(t, 1.0, testutil.ToFloat64(reloads.WithLabelValues(reloadFailure)))
+
+	// the status endpoint reports the failure
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/config/reload", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, file, body["file"])
+	assert.EqualValues(t, 1, body["generation"])
+	assert.Contains(t, body["lastError"], "requestMaxRetries")
+
+	// a missing file is logged and leaves the config as is
+	require.NoError(t, os.Remove(file))
+	r.check()
+	assert.Equal(t, 1, r.Status().Generation)
+}
+
+func TestConfigReloaderWatch(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tr1d1um.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`supportedServices: ["config"]`), 0600))
+	initial, err := readRuntimeConfig(file)
+	require.NoError(t, err)
+
+	r := newConfigReloader(file, 10*time.Millisecond, initial, zap.NewNop())
+	r.start()
+	defer r.Stop()
+
+	require.NoError(t, os.WriteFile(file, []byte(`supportedServices: ["iot"]`), 0600))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(file, later, later))
+
+	assert.Eventually(t, func() bool {
+		return len(r.supportedServices()) == 1 && r.supportedServices()[0] == "iot"
+	}, time.Second, 10*time.Millisecond)
+}
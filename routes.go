@@ -16,6 +16,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/justinas/alice"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/viper"
 	"github.com/xmidt-org/ancla"
 	anclaschema "github.com/xmidt-org/ancla/schema"
@@ -27,6 +28,8 @@ import (
 	"github.com/xmidt-org/sallust/sallusthttp"
 	"github.com/xmidt-org/touchstone"
 	"github.com/xmidt-org/touchstone/touchhttp"
+	"github.com/xmidt-org/tr1d1um/group"
+	"github.com/xmidt-org/tr1d1um/schedule"
 	"github.com/xmidt-org/tr1d1um/stat"
 	"github.com/xmidt-org/tr1d1um/transaction"
 	"github.com/xmidt-org/tr1d1um/translation"
@@ -44,18 +47,35 @@ var (
 
 type primaryEndpointIn struct {
 	fx.In
-	V                           *viper.Viper
-	Router                      *mux.Router `name:"server_primary"`
-	APIRouter                   *mux.Router `name:"api_router"`
-	AuthChain                   alice.Chain `name:"auth_chain"`
-	Tracing                     candlelight.Tracing
-	Logger                      *zap.Logger
-	StatServiceOptions          *stat.ServiceOptions
-	TranslationOptions          *translation.ServiceOptions
-	AuthAcquirer                authAcquirerConfig            `name:"authAcquirer"`
-	ReducedLoggingResponseCodes []int                         `name:"reducedLoggingResponseCodes"`
-	TranslationServices         []string                      `name:"supportedServices"`
-	BearerFingerprint           transaction.FingerprintConfig `name:"bearerFingerprint"`
+	Lifecycle                 fx.Lifecycle
+	V                         *viper.Viper
+	Router                    *mux.Router `name:"server_primary"`
+	APIRouter                 *mux.Router `name:"api_router"`
+	AuthChain                 alice.Chain `name:"auth_chain"`
+	Tracing                   candlelight.Tracing
+	Logger                    *zap.Logger
+	StatServiceOptions        *stat.ServiceOptions
+	TranslationOptions        *translation.ServiceOptions
+	AuthAcquirer              authAcquirerConfig `name:"authAcquirer"`
+	AuthPolicy                authPolicyConfig   `name:"authPolicy"`
+	Config                    *configReloader
+	Timeouts                  transaction.TimeoutPolicy        `name:"requestTimeouts"`
+	WRPPassthrough            translation.WRPPassthrough       `name:"wrpPassthrough"`
+	StrictAccept              bool                             `name:"strictAccept"`
+	RawWRPServices            []string                         `name:"rawWRPServices"`
+	Events                    translation.EventConfig          `name:"events"`
+	EventsSent                *prometheus.CounterVec           `name:"events_sent"`
+	BatchGet                  translation.BatchGetConfig       `name:"batchGet"`
+	BodyLimits                translation.BodyLimits           `name:"bodyLimits"`
+	TableSchemas              translation.TableSchemas         `name:"tableSchemas"`
+	Scripts                   translation.ScriptConfig         `name:"scripts"`
+	CompareAndSwap            translation.CompareAndSwapConfig `name:"compareAndSwap"`
+	Schedule                  schedule.Config                  `name:"schedule"`
+	Groups                    group.Config                     `name:"groups"`
+	BatchStat                 stat.BatchConfig                 `name:"batchStat"`
+	StatWatch                 stat.WatchConfig                 `name:"statWatch"`
+	AuthAcquirerFetches       *prometheus.CounterVec           `name:"auth_acquirer_fetches"`
+	AuthAcquirerFetchDuration prometheus.ObserverVec           `name:"auth_acquirer_fetch_duration_seconds"`
 }
 
 type handleWebhookRoutesIn struct {
@@ -119,22 +139,31 @@ type metricsRoutesIn struct {
 
 func provideServers() fx.Option {
 	return fx.Options(
-		arrange.ProvideKey(reqMaxRetriesKey, 0),
-		arrange.ProvideKey(reqRetryIntervalKey, time.Duration(0)),
 		arrange.ProvideKey("previousVersionSupport", true),
 		arrange.ProvideKey("targetURL", ""),
 		arrange.ProvideKey("WRPSource", ""),
-		arrange.ProvideKey(translationServicesKey, []string{}),
+		arrange.ProvideKey(xmidtClientTLSKey, outboundTLSConfig{}),
+		arrange.ProvideKey(configReloadKey, configReloadConfig{}),
+		arrange.ProvideKey(readinessKey, readinessConfig{}),
+		arrange.ProvideKey(drainKey, drainConfig{}),
+		arrange.ProvideKey(requestTimeoutsKey, transaction.TimeoutPolicy{}),
+		arrange.ProvideKey(wrpPassthroughKey, translation.WRPPassthrough{}),
+		arrange.ProvideKey(strictAcceptKey, false),
+		arrange.ProvideKey(rawWRPServicesKey, []string{}),
+		arrange.ProvideKey(eventsKey, translation.EventConfig{}),
+		arrange.ProvideKey(batchGetKey, translation.BatchGetConfig{}),
+		arrange.ProvideKey(bodyLimitsKey, translation.BodyLimits{}),
+		arrange.ProvideKey(tableSchemasKey, translation.TableSchemas{}),
+		arrange.ProvideKey(scriptsKey, translation.ScriptConfig{}),
+		arrange.ProvideKey(compareAndSwapKey, translation.CompareAndSwapConfig{}),
+		arrange.ProvideKey(scheduleKey, schedule.Config{}),
+		arrange.ProvideKey(groupsKey, group.Config{}),
+		arrange.ProvideKey(batchStatKey, stat.BatchConfig{}),
+		arrange.ProvideKey(statWatchKey, stat.WatchConfig{}),
 		fx.Provide(metricMiddleware),
 		fx.Provide(
-			fx.Annotated{
-				Name:   "reducedLoggingResponseCodes",
-				Target: arrange.UnmarshalKey(reducedTransactionLoggingCodesKey, []int{}),
-			},
-			fx.Annotated{
-				Name:   "bearerFingerprint",
-				Target: arrange.UnmarshalKey(fingerprintCredsKey, transaction.FingerprintConfig{}),
-			},
+			provideConfigReloader,
+			transaction.NewDrainer,
 			fx.Annotated{
 				Name:   "api_router",
 				Target: provideAPIRouter,
@@ -183,14 +212,18 @@ func provideServers() fx.Option {
 		}.Provide(),
 		fx.Invoke(
 			handlePrimaryEndpoint,
+			handleConfigReloadRoutes,
+			handleConfigDumpRoutes,
+			handleReadinessRoutes,
 			handleWebhookRoutes,
 			buildMetricsRoutes,
 			buildAPIAltRouter,
+			handleDrain,
 		),
 	)
 }
 
-func handlePrimaryEndpoint(in primaryEndpointIn) {
+func handlePrimaryEndpoint(in primaryEndpointIn) error {
 	otelMuxOptions := []otelmux.Option{
 		otelmux.WithTracerProvider(in.Tracing.TracerProvider()),
 		otelmux.WithPropagators(in.Tracing.Propagator()),
@@ -199,38 +232,112 @@ func handlePrimaryEndpoint(in primaryEndpointIn) {
 	in.Router.Use(
 		otelmux.Middleware("mainSpan", otelMuxOptions...),
 	)
+	in.APIRouter.Use(newRateLimiter(in.Config.rateLimit).Then)
 
 	if in.V.IsSet(authAcquirerKey) {
-		acquirer, err := createAuthAcquirer(in.AuthAcquirer)
+		acquirer, err := createAuthAcquirer(in.AuthAcquirer, transaction.AcquirerMetrics{
+			Fetches:       in.AuthAcquirerFetches,
+			FetchDuration: in.AuthAcquirerFetchDuration,
+		}, in.Logger)
 		if err != nil {
-			in.Logger.Error("Could not configure auth acquirer", zap.Error(err))
-		} else {
-			in.TranslationOptions.AuthAcquirer = acquirer
-			in.StatServiceOptions.AuthAcquirer = acquirer
-			in.Logger.Info("Outbound request authentication token acquirer enabled")
+			return fmt.Errorf("could not configure auth acquirer: %w", err)
+		}
+		in.TranslationOptions.AuthAcquirer = acquirer
+		in.StatServiceOptions.AuthAcquirer = acquirer
+		if s, ok := acquirer.(interface{ Stop() }); ok {
+			in.Lifecycle.Append(fx.StopHook(s.Stop))
 		}
+		in.Logger.Info("Outbound request authentication token acquirer enabled")
+	}
+
+	outboundAuth, err := createOutboundAuth(in.AuthPolicy, in.TranslationOptions.AuthAcquirer, in.Logger)
+	if err != nil {
+		return err
 	}
+	in.TranslationOptions.AuthPolicy = outboundAuth.Policy
+	in.TranslationOptions.TokenExchanger = outboundAuth.Exchanger
+	in.StatServiceOptions.AuthPolicy = outboundAuth.Policy
+	in.StatServiceOptions.TokenExchanger = outboundAuth.Exchanger
+
 	ss := stat.NewService(in.StatServiceOptions)
 	ts := translation.NewService(in.TranslationOptions)
 
+	watcher := stat.NewWatcher(in.StatWatch, ss)
+	in.Lifecycle.Append(fx.StopHook(watcher.Stop))
+
 	// Must be called before translation.ConfigHandler due to mux path specificity (https://github.com/gorilla/mux#matching-routes).
 	stat.ConfigHandler(&stat.Options{
 		S:                           ss,
 		APIRouter:                   in.APIRouter,
 		Authenticate:                &in.AuthChain,
 		Log:                         in.Logger,
-		ReducedLoggingResponseCodes: in.ReducedLoggingResponseCodes,
-		BearerFingerprint:           in.BearerFingerprint,
+		ReducedLoggingResponseCodes: in.Config.reducedLoggingResponseCodes,
+		BearerFingerprint:           in.Config.bearerFingerprint,
+		Timeouts:                    in.Timeouts,
+		Batch:                       in.BatchStat,
+		Watcher:                     watcher,
 	})
 	translation.ConfigHandler(&translation.Options{
 		S:                           ts,
 		APIRouter:                   in.APIRouter,
 		Authenticate:                &in.AuthChain,
 		Log:                         in.Logger,
-		ValidServices:               in.TranslationServices,
-		ReducedLoggingResponseCodes: in.ReducedLoggingResponseCodes,
-		BearerFingerprint:           in.BearerFingerprint,
+		ValidServices:               in.Config.supportedServices,
+		ReducedLoggingResponseCodes: in.Config.reducedLoggingResponseCodes,
+		BearerFingerprint:           in.Config.bearerFingerprint,
+		Timeouts:                    in.Timeouts,
+		StrictAccept:                in.StrictAccept,
+		WRPPassthrough:              in.WRPPassthrough,
+		RawServices:                 in.RawWRPServices,
+		Events:                      in.Events,
+		EventMetrics:                translation.EventMetrics{Sent: in.EventsSent},
+		BatchGet:                    in.BatchGet,
+		BodyLimits:                  in.BodyLimits,
+		TableSchemas:                in.TableSchemas,
+		Scripts:                     in.Scripts,
+		CompareAndSwap:              in.CompareAndSwap,
 	})
+
+	if in.V.IsSet(scheduleKey) {
+		store, err := schedule.NewFileStore(in.Schedule.File)
+		if err != nil {
+			return fmt.Errorf("could not load scheduled jobs: %w", err)
+		}
+
+		scheduler := schedule.NewScheduler(in.Schedule, ts, outboundAuth, store, in.Logger)
+		in.Lifecycle.Append(fx.StartStopHook(scheduler.Start, scheduler.Stop))
+		schedule.ConfigHandler(&schedule.Options{
+			S:                           scheduler,
+			APIRouter:                   in.APIRouter,
+			Authenticate:                &in.AuthChain,
+			Log:                         in.Logger,
+			ValidServices:               in.Config.supportedServices,
+			ReducedLoggingResponseCodes: in.Config.reducedLoggingResponseCodes,
+			BearerFingerprint:           in.Config.bearerFingerprint,
+			TableSchemas:                in.TableSchemas,
+		})
+		in.Logger.Info("Job scheduler enabled", zap.String("file", in.Schedule.File))
+	}
+
+	if in.V.IsSet(groupsKey) {
+		store, err := group.NewFileStore(in.Groups.File)
+		if err != nil {
+			return fmt.Errorf("could not load device groups: %w", err)
+		}
+
+		group.ConfigHandler(&group.Options{
+			R:                           group.NewRegistry(in.Groups, store, group.StaticInventory(in.Groups.Inventory)),
+			APIRouter:                   in.APIRouter,
+			Authenticate:                &in.AuthChain,
+			Log:                         in.Logger,
+			ReducedLoggingResponseCodes: in.Config.reducedLoggingResponseCodes,
+			BearerFingerprint:           in.Config.bearerFingerprint,
+			BodyLimits:                  in.BodyLimits,
+		})
+		in.Logger.Info("Device groups enabled", zap.String("file", in.Groups.File), zap.Int("inventory", len(in.Groups.Inventory)))
+	}
+
+	return nil
 }
 
 func handleWebhookRoutes(in handleWebhookRoutesIn) error {
@@ -278,7 +385,19 @@ func buildAPIAltRouter(in apiAltRouterIn) {
 	apiAltRouter := in.AlternateRouter.PathPrefix(in.URLPrefix).Subrouter()
 	apiAltRouter.Handle("/device/{deviceid}/{service}", in.APIRouter)
 	apiAltRouter.Handle("/device/{deviceid}/{service}/{parameter}", in.APIRouter)
+	apiAltRouter.Handle("/device/{deviceid}/{service}/batch", in.APIRouter)
+	apiAltRouter.Handle("/device/{deviceid}/{service}/script", in.APIRouter)
 	apiAltRouter.Handle("/device/{deviceid}/stat", in.APIRouter)
+	apiAltRouter.Handle("/device/{deviceid}/stat/watch", in.APIRouter)
+	apiAltRouter.Handle("/wrp/device/{deviceid}/{service}", in.APIRouter)
+	apiAltRouter.Handle("/event/device/{deviceid}/{service}", in.APIRouter)
+	apiAltRouter.Handle("/event/devices/{service}", in.APIRouter)
+	apiAltRouter.Handle("/devices/stat", in.APIRouter)
+	apiAltRouter.Handle("/jobs", in.APIRouter)
+	apiAltRouter.Handle("/jobs/{id}", in.APIRouter)
+	apiAltRouter.Handle("/groups", in.APIRouter)
+	apiAltRouter.Handle("/groups/{name}", in.APIRouter)
+	apiAltRouter.Handle("/groups/{name}/devices", in.APIRouter)
 	apiAltRouter.Handle("/hook", in.APIRouter)
 	apiAltRouter.Handle("/hooks", in.APIRouter)
 }
@@ -100,6 +100,18 @@ func TestBuildAPIAltRouter(t *testing.T) {
 		{name: "device service route", path: "/api/v3/device/mac123/reboot", expectCode: http.StatusAccepted},
 		{name: "device service parameter route", path: "/api/v3/device/mac123/get/value", expectCode: http.StatusAccepted},
 		{name: "stat route", path: "/api/v3/device/mac123/stat", expectCode: http.StatusAccepted},
+		{name: "stat watch route", path: "/api/v3/device/mac123/stat/watch", expectCode: http.StatusAccepted},
+		{name: "batch route", path: "/api/v3/device/mac123/config/batch", expectCode: http.StatusAccepted},
+		{name: "script route", path: "/api/v3/device/mac123/config/script", expectCode: http.StatusAccepted},
+		{name: "raw WRP route", path: "/api/v3/wrp/device/mac123/iot", expectCode: http.StatusAccepted},
+		{name: "device event route", path: "/api/v3/event/device/mac123/iot", expectCode: http.StatusAccepted},
+		{name: "devices event route", path: "/api/v3/event/devices/iot", expectCode: http.StatusAccepted},
+		{name: "devices stat route", path: "/api/v3/devices/stat", expectCode: http.StatusAccepted},
+		{name: "jobs route", path: "/api/v3/jobs", expectCode: http.StatusAccepted},
+		{name: "job route", path: "/api/v3/jobs/1234", expectCode: http.StatusAccepted},
+		{name: "groups route", path: "/api/v3/groups", expectCode: http.StatusAccepted},
+		{name: "group route", path: "/api/v3/groups/lab", expectCode: http.StatusAccepted},
+		{name: "group devices route", path: "/api/v3/groups/lab/devices", expectCode: http.StatusAccepted},
 		{name: "hook route", path: "/api/v3/hook", expectCode: http.StatusAccepted},
 		{name: "hooks route", path: "/api/v3/hooks", expectCode: http.StatusAccepted},
 		{name: "unmatched route", path: "/api/v3/not-found", expectCode: http.StatusNotFound},
@@ -109,21 +121,29 @@ func TestBuildAPIAltRouter(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			primary := mux.NewRouter()
 			api := provideAPIRouter(apiRouterIn{PrimaryRouter: primary, URLPrefix: "/api/v3"})
-			api.HandleFunc("/device/{deviceid}/{service}", func(w http.ResponseWriter, _ *http.Request) {
-				w.WriteHeader(http.StatusAccepted)
-			})
-			api.HandleFunc("/device/{deviceid}/{service}/{parameter}", func(w http.ResponseWriter, _ *http.Request) {
-				w.WriteHeader(http.StatusAccepted)
-			})
-			api.HandleFunc("/device/{deviceid}/stat", func(w http.ResponseWriter, _ *http.Request) {
-				w.WriteHeader(http.StatusAccepted)
-			})
-			api.HandleFunc("/hook", func(w http.ResponseWriter, _ *http.Request) {
-				w.WriteHeader(http.StatusAccepted)
-			})
-			api.HandleFunc("/hooks", func(w http.ResponseWriter, _ *http.Request) {
-				w.WriteHeader(http.StatusAccepted)
-			})
+			for _, path := range []string{
+				"/device/{deviceid}/{service}",
+				"/device/{deviceid}/{service}/{parameter}",
+				"/device/{deviceid}/stat",
+				"/device/{deviceid}/stat/watch",
+				"/device/{deviceid}/{service}/batch",
+				"/device/{deviceid}/{service}/script",
+				"/wrp/device/{deviceid}/{service}",
+				"/event/device/{deviceid}/{service}",
+				"/event/devices/{service}",
+				"/devices/stat",
+				"/jobs",
+				"/jobs/{id}",
+				"/groups",
+				"/groups/{name}",
+				"/groups/{name}/devices",
+				"/hook",
+				"/hooks",
+			} {
+				api.HandleFunc(path, func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusAccepted)
+				})
+			}
 
 			alternate := mux.NewRouter()
 			buildAPIAltRouter(apiAltRouterIn{APIRouter: api, AlternateRouter: alternate, URLPrefix: "/api/v3"})
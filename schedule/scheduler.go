@@ -0,0 +1,488 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package schedule
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	mathrand "math/rand/v2"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/tr1d1um/translation"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+)
+
+// Job statuses
+const (
+	StatusScheduled = "scheduled"
+	StatusRunning   = "running"
+	StatusDone      = "done"
+	StatusCanceled  = "canceled"
+)
+
+// Defaults for Config.
+const (
+	DefaultPollInterval = 10 * time.Second
+	DefaultConcurrency  = 10
+	DefaultMaxDevices   = 1000
+	DefaultHistorySize  = 10
+	DefaultMinEvery     = time.Minute
+)
+
+// Job errors
+var (
+	ErrMissingDevices = transaction.NewBadRequestError(errors.New("at least one device is required"))
+	ErrTooManyDevices = transaction.NewBadRequestError(errors.New("too many devices"))
+	ErrInvalidEvery   = transaction.NewBadRequestError(errors.New("every must be a duration of at least the minimum interval, such as '24h'"))
+	ErrJobFinished    = transaction.NewCodedError(errors.New("job is already done or canceled"), http.StatusConflict)
+	ErrNoServiceAuth  = transaction.NewBadRequestError(errors.New("jobs can only be scheduled for services using the service auth mode"))
+)
+
+// Config configures the scheduler.
+type Config struct {
+	// File is the path of the file jobs are saved to.
+	// (Optional) By default, jobs are kept in memory only.
+	File string
+
+	// PollInterval is how often due jobs are looked for.
+	// (Optional) Defaults to DefaultPollInterval.
+	PollInterval time.Duration
+
+	// Jitter is the maximum random delay before each device command of a run,
+	// spreading the load of jobs targeting many devices.
+	// (Optional)
+	Jitter time.Duration
+
+	// Concurrency is the maximum number of device commands in flight across
+	// all jobs.
+	// (Optional) Defaults to DefaultConcurrency.
+	Concurrency int
+
+	// MaxDevices is the maximum number of devices of a job.
+	// (Optional) Defaults to DefaultMaxDevices.
+	MaxDevices int
+
+	// HistorySize is the number of runs kept in the history of a job.
+	// (Optional) Defaults to DefaultHistorySize.
+	HistorySize int
+
+	// MinEvery is the minimum interval between runs of recurring jobs.
+	// (Optional) Defaults to DefaultMinEvery.
+	MinEvery time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.PollInterval <= 0 {
+		c.PollInterval = DefaultPollInterval
+	}
+	if c.Concurrency <= 0 {
+		c.Concurrency = DefaultConcurrency
+	}
+	if c.MaxDevices <= 0 {
+		c.MaxDevices = DefaultMaxDevices
+	}
+	if c.HistorySize <= 0 {
+		c.HistorySize = DefaultHistorySize
+	}
+	if c.MinEvery <= 0 {
+		c.MinEvery = DefaultMinEvery
+	}
+	return c
+}
+
+// Job is a WDMP command scheduled to be sent to a list of devices.
+type Job struct {
+	ID      string   `json:"id"`
+	Service string   `json:"service"`
+	Devices []string `json:"devices"`
+
+	// Command is a SET, ADD_ROW, REPLACE_ROWS or DELETE_ROW command, in the
+	// form of a script step.
+	Command json.RawMessage `json:"command"`
+
+	// At is the time of the next run.
+	At time.Time `json:"at"`
+
+	// Every is the interval between runs of recurring jobs, e.g. "24h".
+	Every string `json:"every,omitempty"`
+
+	// Owner and PartnerIDs are the principal and the partners of the token
+	// of the job's creator.
+	Owner      string    `json:"owner,omitempty"`
+	PartnerIDs []string  `json:"partnerIDs,omitempty"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"createdAt"`
+
+	// Runs are the latest runs of the job, oldest first.
+	Runs []Run `json:"runs,omitempty"`
+}
+
+// Run is the outcome of a run of a job.
+type Run struct {
+	Start   time.Time         `json:"start"`
+	End     time.Time         `json:"end"`
+	Devices map[string]Result `json:"devices"`
+}
+
+// Result is the outcome of the command sent to a device.
+type Result struct {
+	StatusCode int    `json:"statusCode,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// Scheduler runs jobs at their scheduled time through a translation.Service.
+// Jobs run without their creator's credentials, so only the services whose
+// requests are made with tr1d1um's own identity can be scheduled.
+type Scheduler struct {
+	config  Config
+	service translation.Service
+	auth    transaction.OutboundAuth
+	store   Store
+	logger  *zap.Logger
+	now     func() time.Time
+	sem     chan struct{}
+
+	// mu serializes the changes of job statuses.
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewScheduler returns a Scheduler of the jobs in store. auth is the outbound
+// auth of s.
+func NewScheduler(c Config, s translation.Service, auth transaction.OutboundAuth, store Store, logger *zap.Logger) *Scheduler {
+	c = c.withDefaults()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Scheduler{
+		config:  c,
+		service: s,
+		auth:    auth,
+		store:   store,
+		logger:  logger,
+		now:     time.Now,
+		sem:     make(chan struct{}, c.Concurrency),
+		cancels: make(map[string]context.CancelFunc),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// genID generates a random ID.
+func genID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// Create checks and schedules a job. Jobs without a time run right away.
+func (s *Scheduler) Create(job Job) (Job, error) {
+	if s.auth.Mode(transaction.APIDevice, job.Service) != transaction.AuthModeService {
+		return Job{}, ErrNoServiceAuth
+	}
+	if len(job.Devices) == 0 {
+		return Job{}, ErrMissingDevices
+	}
+	if len(job.Devices) > s.config.MaxDevices {
+		return Job{}, ErrTooManyDevices
+	}
+
+	devices := make([]string, 0, len(job.Devices))
+	for _, id := range job.Devices {
+		deviceID, err := wrp.ParseDeviceID(id)
+		if err != nil {
+			return Job{}, transaction.NewBadRequestError(err)
+		}
+		if !slices.Contains(devices, string(deviceID)) {
+			devices = append(devices, string(deviceID))
+		}
+	}
+
+	if _, err := translation.CommandPayload(job.Command, nil); err != nil {
+		return Job{}, err
+	}
+
+	if job.Every != "" {
+		if every, err := time.ParseDuration(job.Every); err != nil || every < s.config.MinEvery {
+			return Job{}, ErrInvalidEvery
+		}
+	}
+
+	job.ID, job.Devices, job.Status, job.CreatedAt, job.Runs = genID(), devices, StatusScheduled, s.now(), nil
+	if job.At.IsZero() {
+		job.At = job.CreatedAt
+	}
+
+	return job, s.store.Put(job)
+}
+
+// recurring reports whether a job runs every positive interval.
+func recurring(job Job) bool {
+	every, err := time.ParseDuration(job.Every)
+	return err == nil && every > 0
+}
+
+// Get returns a job.
+func (s *Scheduler) Get(id string) (Job, error) {
+	return s.store.Get(id)
+}
+
+// List returns all jobs, ordered by their next run time.
+func (s *Scheduler) List() ([]Job, error) {
+	jobs, err := s.store.List()
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(jobs, func(a, b Job) int {
+		return a.At.Compare(b.At)
+	})
+	return jobs, nil
+}
+
+// Cancel cancels a job, stopping its run if it's running.
+func (s *Scheduler) Cancel(id string) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, err := s.store.Get(id)
+	if err != nil {
+		return Job{}, err
+	}
+	if job.Status == StatusDone || job.Status == StatusCanceled {
+		return job, ErrJobFinished
+	}
+
+	job.Status = StatusCanceled
+	if err = s.store.Put(job); err != nil {
+		return Job{}, err
+	}
+	if cancel, ok := s.cancels[id]; ok {
+		cancel()
+	}
+
+	return job, nil
+}
+
+// Start reschedules the jobs whose run was interrupted by a stop and starts
+// looking for due jobs.
+func (s *Scheduler) Start() error {
+	jobs, err := s.store.List()
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if job.Status == StatusRunning {
+			job.Status = StatusScheduled
+			if err = s.store.Put(job); err != nil {
+				return err
+			}
+		}
+	}
+
+	s.wg.Add(1)
+	go s.poll()
+	return nil
+}
+
+// Stop stops looking for due jobs and interrupts the running ones, waiting
+// for them until ctx is done. Interrupted jobs run again after a restart.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) poll() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.runDue()
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDue starts the runs of the scheduled jobs whose time came.
+func (s *Scheduler) runDue() {
+	jobs, err := s.store.List()
+	if err != nil {
+		s.logger.Error("failed to list scheduled jobs", zap.Error(err))
+		return
+	}
+
+	now := s.now()
+	for _, job := range jobs {
+		if job.Status != StatusScheduled || job.At.After(now) {
+			continue
+		}
+
+		if ctx, ok := s.startRun(job.ID); ok {
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.run(ctx, job)
+			}()
+		}
+	}
+}
+
+// startRun marks a job as running, unless it was canceled meanwhile.
+func (s *Scheduler) startRun(id string) (context.Context, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, err := s.store.Get(id)
+	if err != nil || job.Status != StatusScheduled {
+		return nil, false
+	}
+
+	job.Status = StatusRunning
+	if err = s.store.Put(job); err != nil {
+		s.logger.Error("failed to start job", zap.String("job", id), zap.Error(err))
+		return nil, false
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.cancels[id] = cancel
+	return ctx, true
+}
+
+// run sends the command of a job to its devices and records the outcome.
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	r := Run{
+		Start:   s.now(),
+		Devices: make(map[string]Result, len(job.Devices)),
+	}
+
+	payload, err := translation.CommandPayload(job.Command, nil)
+	if err != nil {
+		for _, device := range job.Devices {
+			r.Devices[device] = Result{StatusCode: http.StatusBadRequest, Message: err.Error()}
+		}
+	} else {
+		var (
+			mu sync.Mutex
+			wg sync.WaitGroup
+		)
+		for _, device := range job.Devices {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result := s.send(ctx, job, device, payload)
+
+				mu.Lock()
+				r.Devices[device] = result
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+	}
+
+	r.End = s.now()
+	s.finishRun(job.ID, r)
+}
+
+// send sends the command of a job to a device, after a random delay of up to
+// the configured jitter.
+func (s *Scheduler) send(ctx context.Context, job Job, device string, payload []byte) Result {
+	canceled := Result{Message: "not sent, the run was interrupted"}
+
+	if s.config.Jitter > 0 {
+		select {
+		case <-time.After(mathrand.N(s.config.Jitter)):
+		case <-ctx.Done():
+			return canceled
+		}
+	}
+
+	select {
+	case s.sem <- struct{}{}:
+		defer func() { <-s.sem }()
+	case <-ctx.Done():
+		return canceled
+	}
+
+	msg := &wrp.Message{
+		Type:            wrp.SimpleRequestResponseMessageType,
+		Destination:     fmt.Sprintf("%s/%s", device, job.Service),
+		TransactionUUID: genID(),
+		PartnerIDs:      job.PartnerIDs,
+		Payload:         payload,
+	}
+
+	// jobs run without the caller's credentials
+	result, _ := translation.DeviceResult(s.service.SendWRP(ctx, msg, ""))
+	return Result(result)
+}
+
+// finishRun records a run in the history of its job and schedules the next
+// run of recurring jobs.
+func (s *Scheduler) finishRun(id string, r Run) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cancel, ok := s.cancels[id]; ok {
+		cancel()
+		delete(s.cancels, id)
+	}
+
+	job, err := s.store.Get(id)
+	if err != nil {
+		s.logger.Error("failed to record job run", zap.String("job", id), zap.Error(err))
+		return
+	}
+
+	job.Runs = append(job.Runs, r)
+	if len(job.Runs) > s.config.HistorySize {
+		job.Runs = job.Runs[len(job.Runs)-s.config.HistorySize:]
+	}
+
+	switch {
+	case job.Status == StatusCanceled:
+	case s.ctx.Err() != nil:
+		// the scheduler stopped, the job runs again after a restart
+		job.Status = StatusScheduled
+	case recurring(job):
+		// the next run is the first one after the end of this one
+		every, _ := time.ParseDuration(job.Every)
+		if !job.At.After(r.End) {
+			job.At = job.At.Add((r.End.Sub(job.At)/every + 1) * every)
+		}
+		job.Status = StatusScheduled
+	default:
+		job.Status = StatusDone
+	}
+
+	if err = s.store.Put(job); err != nil {
+		s.logger.Error("failed to record job run", zap.String("job", id), zap.Error(err))
+	}
+}
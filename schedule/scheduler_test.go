@@ -0,0 +1,258 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+)
+
+var (
+	testNow     = time.Date(2026, 11, 1, 2, 0, 0, 0, time.UTC)
+	testCommand = json.RawMessage(`{"command": "SET", "parameters": [{"name": "Device.A", "value": "v", "dataType": 0}]}`)
+)
+
+// testService answers commands with a WDMP status code, unless send is set.
+type testService struct {
+	mu   sync.Mutex
+	sent []*wrp.Message
+	send func(context.Context, *wrp.Message) (*transaction.XmidtResponse, error)
+}
+
+func (s *testService) SendWRP(ctx context.Context, msg *wrp.Message, _ string) (*transaction.XmidtResponse, error) {
+	s.mu.Lock()
+	s.sent = append(s.sent, msg)
+	s.mu.Unlock()
+
+	if s.send != nil {
+		return s.send(ctx, msg)
+	}
+	return &transaction.XmidtResponse{
+		Code: http.StatusOK,
+		Body: wrp.MustEncode(&wrp.Message{Type: wrp.SimpleRequestResponseMessageType, Payload: []byte(`{"statusCode": 200, "message": "Success"}`)}, wrp.Msgpack),
+	}, nil
+}
+
+func newTestScheduler(t *testing.T, s *testService) *Scheduler {
+	store, err := NewFileStore("")
+	require.NoError(t, err)
+
+	auth := transaction.OutboundAuth{Acquirer: &transaction.BasicAcquirer{Token: "Basic xyz=="}}
+	scheduler := NewScheduler(Config{HistorySize: 2}, s, auth, store, zap.NewNop())
+	scheduler.now = func() time.Time { return testNow }
+	return scheduler
+}
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		description string
+		job         Job
+		expectedErr string
+	}{
+		{
+			description: "no devices",
+			job:         Job{Service: "config", Command: testCommand},
+			expectedErr: ErrMissingDevices.Error(),
+		},
+		{
+			description: "invalid device",
+			job:         Job{Service: "config", Devices: []string{"nope"}, Command: testCommand},
+			expectedErr: "invalid device name",
+		},
+		{
+			description: "invalid command",
+			job:         Job{Service: "config", Devices: []string{"mac:112233445566"}, Command: json.RawMessage(`{"command": "GET"}`)},
+			expectedErr: "unsupported command 'GET'",
+		},
+		{
+			description: "invalid interval",
+			job:         Job{Service: "config", Devices: []string{"mac:112233445566"}, Command: testCommand, Every: "-1h"},
+			expectedErr: ErrInvalidEvery.Error(),
+		},
+		{
+			description: "interval below the minimum",
+			job:         Job{Service: "config", Devices: []string{"mac:112233445566"}, Command: testCommand, Every: "1ns"},
+			expectedErr: ErrInvalidEvery.Error(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := newTestScheduler(t, &testService{}).Create(tc.job)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedErr)
+		})
+	}
+
+	t.Run("passthrough auth", func(t *testing.T) {
+		s := newTestScheduler(t, &testService{})
+		s.auth.Policy = transaction.AuthPolicy{Services: map[string]string{"config": transaction.AuthModePassThrough}}
+		_, err := s.Create(Job{Service: "config", Devices: []string{"mac:112233445566"}, Command: testCommand})
+		assert.Equal(t, ErrNoServiceAuth, err)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		s := newTestScheduler(t, &testService{})
+		job, err := s.Create(Job{Service: "config", Devices: []string{"MAC:11-22-33-44-55-66", "mac:112233445566"}, Command: testCommand})
+		require.NoError(err)
+		assert.Len(job.ID, 32)
+		assert.Equal([]string{"mac:112233445566"}, job.Devices)
+		assert.Equal(StatusScheduled, job.Status)
+		assert.Equal(testNow, job.At)
+
+		stored, err := s.Get(job.ID)
+		require.NoError(err)
+		assert.Equal(job.ID, stored.ID)
+	})
+}
+
+func TestRunDue(t *testing.T) {
+	t.Run("one time", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		service := &testService{}
+		s := newTestScheduler(t, service)
+		due, err := s.Create(Job{Service: "config", Devices: []string{"mac:112233445566", "mac:aabbccddeeff"}, Command: testCommand, PartnerIDs: []string{"comcast"}})
+		require.NoError(err)
+		later, err := s.Create(Job{Service: "config", Devices: []string{"mac:112233445566"}, Command: testCommand, At: testNow.Add(time.Hour)})
+		require.NoError(err)
+
+		s.runDue()
+		s.wg.Wait()
+
+		require.Len(service.sent, 2)
+		for _, msg := range service.sent {
+			assert.True(strings.HasSuffix(msg.Destination, "/config"))
+			assert.Equal([]string{"comcast"}, msg.PartnerIDs)
+			assert.JSONEq(`{"command": "SET", "parameters": [{"name": "Device.A", "value": "v", "dataType": 0}]}`, string(msg.Payload))
+		}
+
+		job, err := s.Get(due.ID)
+		require.NoError(err)
+		assert.Equal(StatusDone, job.Status)
+		require.Len(job.Runs, 1)
+		assert.Equal(map[string]Result{
+			"mac:112233445566": {StatusCode: http.StatusOK, Message: "Success"},
+			"mac:aabbccddeeff": {StatusCode: http.StatusOK, Message: "Success"},
+		}, job.Runs[0].Devices)
+
+		job, err = s.Get(later.ID)
+		require.NoError(err)
+		assert.Equal(StatusScheduled, job.Status)
+		assert.Empty(job.Runs)
+	})
+
+	t.Run("recurring", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		service := &testService{send: func(context.Context, *wrp.Message) (*transaction.XmidtResponse, error) {
+			return nil, errors.New("connection refused")
+		}}
+		s := newTestScheduler(t, service)
+		created, err := s.Create(Job{Service: "config", Devices: []string{"mac:112233445566"}, Command: testCommand, At: testNow.Add(-90 * time.Minute), Every: "1h"})
+		require.NoError(err)
+
+		for i := 0; i < 3; i++ {
+			s.runDue()
+			s.wg.Wait()
+			s.now = func() time.Time { return testNow.Add(time.Duration(i+1) * time.Hour) }
+		}
+
+		job, err := s.Get(created.ID)
+		require.NoError(err)
+		assert.Equal(StatusScheduled, job.Status)
+		assert.Equal(testNow.Add(150*time.Minute), job.At)
+
+		// the history is capped
+		require.Len(job.Runs, 2)
+		assert.Equal(Result{StatusCode: http.StatusInternalServerError, Message: transaction.ErrTr1d1umInternal.Error()}, job.Runs[1].Devices["mac:112233445566"])
+	})
+
+	t.Run("run longer than the interval", func(t *testing.T) {
+		s := newTestScheduler(t, &testService{})
+		created, err := s.Create(Job{Service: "config", Devices: []string{"mac:112233445566"}, Command: testCommand, At: testNow, Every: "1m"})
+		require.NoError(t, err)
+
+		s.finishRun(created.ID, Run{Start: testNow, End: testNow.Add(10*time.Minute + 30*time.Second)})
+		job, err := s.Get(created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, testNow.Add(11*time.Minute), job.At)
+	})
+}
+
+func TestCancel(t *testing.T) {
+	t.Run("scheduled", func(t *testing.T) {
+		s := newTestScheduler(t, &testService{})
+		created, err := s.Create(Job{Service: "config", Devices: []string{"mac:112233445566"}, Command: testCommand, At: testNow.Add(time.Hour)})
+		require.NoError(t, err)
+
+		job, err := s.Cancel(created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, StatusCanceled, job.Status)
+
+		_, err = s.Cancel(created.ID)
+		assert.Equal(t, ErrJobFinished, err)
+
+		_, err = s.Cancel("unknown")
+		assert.Equal(t, ErrJobNotFound, err)
+	})
+
+	t.Run("running", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		started := make(chan struct{})
+		service := &testService{send: func(ctx context.Context, _ *wrp.Message) (*transaction.XmidtResponse, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}}
+		s := newTestScheduler(t, service)
+		created, err := s.Create(Job{Service: "config", Devices: []string{"mac:112233445566"}, Command: testCommand})
+		require.NoError(err)
+
+		s.runDue()
+		<-started
+		_, err = s.Cancel(created.ID)
+		require.NoError(err)
+		s.wg.Wait()
+
+		job, err := s.Get(created.ID)
+		require.NoError(err)
+		assert.Equal(StatusCanceled, job.Status)
+		assert.Len(job.Runs, 1)
+	})
+}
+
+func TestStartStop(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	s := newTestScheduler(t, &testService{})
+	require.NoError(s.store.Put(Job{ID: "interrupted", Service: "config", Devices: []string{"mac:112233445566"}, Command: testCommand, At: testNow.Add(time.Hour), Status: StatusRunning}))
+
+	require.NoError(s.Start())
+	require.NoError(s.Stop(context.Background()))
+
+	job, err := s.Get("interrupted")
+	require.NoError(err)
+	assert.Equal(StatusScheduled, job.Status)
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/xmidt-org/tr1d1um/transaction"
+)
+
+// ErrJobNotFound is returned for unknown job IDs.
+var ErrJobNotFound = transaction.NewCodedError(errors.New("job not found"), http.StatusNotFound)
+
+// Store persists jobs.
+type Store interface {
+	// Get returns the job with the given ID, or ErrJobNotFound.
+	Get(id string) (Job, error)
+
+	// List returns all jobs.
+	List() ([]Job, error)
+
+	// Put creates or replaces a job.
+	Put(Job) error
+}
+
+// FileStore is a Store keeping jobs in memory and saving them to a JSON file
+// on every change.
+type FileStore struct {
+	path string
+
+	mu   sync.Mutex
+	jobs map[string]Job
+}
+
+// NewFileStore returns a FileStore saving jobs to the file at path, loading
+// the jobs the file already has. An empty path keeps jobs in memory only.
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{
+		path: path,
+		jobs: make(map[string]Job),
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var jobs []Job
+	if err = json.Unmarshal(data, &jobs); err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		s.jobs[job.ID] = job
+	}
+
+	return s, nil
+}
+
+// Get returns the job with the given ID.
+func (s *FileStore) Get(id string) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// List returns all jobs, ordered by ID.
+func (s *FileStore) List() ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.list(), nil
+}
+
+func (s *FileStore) list() []Job {
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, id := range slices.Sorted(maps.Keys(s.jobs)) {
+		jobs = append(jobs, s.jobs[id])
+	}
+	return jobs
+}
+
+// Put creates or replaces a job and saves the jobs to the file. The job is
+// left unchanged if they can't be saved.
+func (s *FileStore) Put(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, existed := s.jobs[job.ID]
+	s.jobs[job.ID] = job
+
+	if err := s.save(); err != nil {
+		if existed {
+			s.jobs[job.ID] = previous
+		} else {
+			delete(s.jobs, job.ID)
+		}
+		return err
+	}
+	return nil
+}
+
+// save writes the jobs to a temporary file first, so that the file is never
+// left partially written.
+func (s *FileStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.list(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package schedule
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		s, err := NewFileStore("")
+		require.NoError(err)
+
+		_, err = s.Get("a")
+		assert.Equal(ErrJobNotFound, err)
+
+		require.NoError(s.Put(Job{ID: "b", Status: StatusScheduled}))
+		require.NoError(s.Put(Job{ID: "a", Status: StatusScheduled}))
+		require.NoError(s.Put(Job{ID: "a", Status: StatusDone}))
+
+		job, err := s.Get("a")
+		require.NoError(err)
+		assert.Equal(StatusDone, job.Status)
+
+		jobs, err := s.List()
+		require.NoError(err)
+		require.Len(jobs, 2)
+		assert.Equal("a", jobs[0].ID)
+		assert.Equal("b", jobs[1].ID)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		path := filepath.Join(t.TempDir(), "jobs.json")
+		s, err := NewFileStore(path)
+		require.NoError(err)
+
+		at := time.Date(2026, 11, 1, 2, 0, 0, 0, time.UTC)
+		require.NoError(s.Put(Job{ID: "a", Service: "config", Devices: []string{"mac:112233445566"}, At: at, Status: StatusScheduled}))
+
+		reloaded, err := NewFileStore(path)
+		require.NoError(err)
+		job, err := reloaded.Get("a")
+		require.NoError(err)
+		assert.Equal("config", job.Service)
+		assert.True(at.Equal(job.At))
+
+		_, err = os.Stat(path + ".tmp")
+		assert.True(os.IsNotExist(err))
+	})
+
+	t.Run("save failure", func(t *testing.T) {
+		s, err := NewFileStore(filepath.Join(t.TempDir(), "missing", "jobs.json"))
+		require.NoError(t, err)
+
+		assert.Error(t, s.Put(Job{ID: "a"}))
+		_, err = s.Get("a")
+		assert.Equal(t, ErrJobNotFound, err)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "jobs.json")
+		require.NoError(t, os.WriteFile(path, []byte("{"), 0600))
+
+		_, err := NewFileStore(path)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,216 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/xmidt-org/candlelight"
+	"github.com/xmidt-org/sallust"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/tr1d1um/translation"
+	"go.uber.org/zap"
+)
+
+const contentTypeHeaderKey = "Content-Type"
+
+// maxRequestSize is the maximum size, in bytes, of job creation requests.
+const maxRequestSize = 1024 * 1024
+
+// Job request errors
+var (
+	ErrInvalidJob     = transaction.NewBadRequestError(errors.New("job is invalid"))
+	ErrInvalidService = transaction.NewBadRequestError(errors.New("unsupported Service"))
+)
+
+// Options wraps the properties needed to set up the scheduler server
+type Options struct {
+	S *Scheduler
+
+	//APIRouter is assumed to be a subrouter with the API prefix path (i.e. 'api/v2')
+	APIRouter                   *mux.Router
+	Authenticate                *alice.Chain
+	Log                         *zap.Logger
+	ValidServices               func() []string
+	ReducedLoggingResponseCodes func() []int
+	BearerFingerprint           func() transaction.FingerprintConfig
+
+	//TableSchemas checks the rows of the ADD_ROW and REPLACE_ROWS commands of jobs.
+	TableSchemas translation.TableSchemas
+}
+
+// createRequest is the body of job creation requests.
+type createRequest struct {
+	Service string          `json:"service"`
+	Devices []string        `json:"devices"`
+	Command json.RawMessage `json:"command"`
+	At      time.Time       `json:"at"`
+	Every   string          `json:"every,omitempty"`
+}
+
+type jobsResponse struct {
+	Jobs []Job `json:"jobs"`
+}
+
+// ConfigHandler sets up the server that powers the scheduler endpoints:
+// POST /jobs creates a job, GET /jobs lists them, GET /jobs/{id} returns one
+// and DELETE /jobs/{id} cancels it.
+func ConfigHandler(c *Options) {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(transaction.ErrorLogEncoder(sallust.Get, encodeError)),
+		kithttp.ServerFinalizer(transaction.LogFunc(c.ReducedLoggingResponseCodes)),
+	}
+
+	createHandler := kithttp.NewServer(
+		func(_ context.Context, request interface{}) (interface{}, error) {
+			return c.S.Create(request.(Job))
+		},
+		decodeCreateRequest(c.ValidServices, c.TableSchemas),
+		encodeJobResponse(http.StatusCreated),
+		opts...,
+	)
+
+	listHandler := kithttp.NewServer(
+		func(ctx context.Context, _ interface{}) (interface{}, error) {
+			jobs, err := c.S.List()
+			if err != nil {
+				return nil, err
+			}
+
+			caller := transaction.GetCaller(ctx)
+			resp := &jobsResponse{Jobs: []Job{}}
+			for _, job := range jobs {
+				if caller.Allowed(job.Owner, job.PartnerIDs) {
+					resp.Jobs = append(resp.Jobs, job)
+				}
+			}
+			return resp, nil
+		},
+		kithttp.NopRequestDecoder,
+		encodeJobResponse(http.StatusOK),
+		opts...,
+	)
+
+	getHandler := kithttp.NewServer(
+		jobEndpoint(c.S, c.S.Get),
+		decodeJobID,
+		encodeJobResponse(http.StatusOK),
+		opts...,
+	)
+
+	cancelHandler := kithttp.NewServer(
+		jobEndpoint(c.S, c.S.Cancel),
+		decodeJobID,
+		encodeJobResponse(http.StatusOK),
+		opts...,
+	)
+
+	welcome := transaction.WelcomeFunc(c.BearerFingerprint)
+	handle := func(path string, h http.Handler, methods ...string) {
+		c.APIRouter.Handle(path, c.Authenticate.Then(candlelight.EchoFirstTraceNodeInfo(candlelight.Tracing{}.Propagator(), false)(welcome(h)))).
+			Methods(methods...)
+	}
+
+	handle("/jobs", createHandler, http.MethodPost)
+	handle("/jobs", listHandler, http.MethodGet)
+	handle("/jobs/{id}", getHandler, http.MethodGet)
+	handle("/jobs/{id}", cancelHandler, http.MethodDelete)
+}
+
+// jobEndpoint applies f to a job the caller is allowed to see. Other jobs
+// are reported as not found.
+func jobEndpoint(s *Scheduler, f func(string) (Job, error)) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		id := request.(string)
+		job, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		if !transaction.GetCaller(ctx).Allowed(job.Owner, job.PartnerIDs) {
+			return nil, ErrJobNotFound
+		}
+		return f(id)
+	}
+}
+
+func decodeJobID(_ context.Context, r *http.Request) (interface{}, error) {
+	return mux.Vars(r)["id"], nil
+}
+
+// decodeCreateRequest returns a decoder of job creation requests, owned by the
+// caller and run for the partners of their token. The rows of their commands
+// are checked against schemas.
+func decodeCreateRequest(services func() []string, schemas translation.TableSchemas) kithttp.DecodeRequestFunc {
+	return func(ctx context.Context, r *http.Request) (interface{}, error) {
+		var req createRequest
+
+		dec := json.NewDecoder(io.LimitReader(r.Body, maxRequestSize))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			return nil, ErrInvalidJob
+		}
+
+		if !slices.Contains(services(), req.Service) {
+			return nil, ErrInvalidService
+		}
+		if _, err := translation.CommandPayload(req.Command, schemas); err != nil {
+			return nil, err
+		}
+
+		caller := transaction.GetCaller(ctx)
+		return Job{
+			Service:    req.Service,
+			Devices:    req.Devices,
+			Command:    req.Command,
+			At:         req.At,
+			Every:      req.Every,
+			Owner:      caller.Principal,
+			PartnerIDs: caller.PartnerIDs,
+		}, nil
+	}
+}
+
+func encodeJobResponse(code int) kithttp.EncodeResponseFunc {
+	return func(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+		w.Header().Set(contentTypeHeaderKey, "application/json")
+		w.Header().Set(candlelight.HeaderWPATIDKeyName, getTID(ctx))
+		w.WriteHeader(code)
+		return json.NewEncoder(w).Encode(response)
+	}
+}
+
+func getTID(ctx context.Context) string {
+	tid, _ := ctx.Value(transaction.ContextKeyRequestTID).(string)
+	return tid
+}
+
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set(contentTypeHeaderKey, "application/json")
+	w.Header().Set(candlelight.HeaderWPATIDKeyName, getTID(ctx))
+
+	var ce transaction.CodedError
+	if errors.As(err, &ce) {
+		w.WriteHeader(ce.StatusCode())
+	} else {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		//the real error is logged into our system before encodeError() is called
+		//the idea behind masking it is to not send the external API consumer internal error messages
+		err = transaction.ErrTr1d1umInternal
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": err.Error(),
+	})
+}
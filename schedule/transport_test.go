@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package schedule
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/bascule"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/tr1d1um/translation"
+	"go.uber.org/zap"
+)
+
+func TestConfigHandler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	router := mux.NewRouter()
+	ConfigHandler(&Options{
+		S:                           newTestScheduler(t, &testService{}),
+		APIRouter:                   router,
+		Authenticate:                &alice.Chain{},
+		Log:                         zap.NewNop(),
+		ValidServices:               func() []string { return []string{"config"} },
+		ReducedLoggingResponseCodes: func() []int { return nil },
+		BearerFingerprint:           func() transaction.FingerprintConfig { return transaction.FingerprintConfig{} },
+		TableSchemas:                translation.TableSchemas{{Table: "Device.T.", Columns: []string{"Name"}}},
+	})
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, "http://localhost"+path, bytes.NewBufferString(body)))
+		return w
+	}
+
+	w := serve(http.MethodPost, "/jobs", `{"service": "config", "devices": ["mac:112233445566"], "command": {"command": "DELETE_ROW", "row": "Device.T.1."}, "at": "2026-11-02T02:00:00Z"}`)
+	require.Equal(http.StatusCreated, w.Code)
+
+	var job Job
+	require.NoError(json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(StatusScheduled, job.Status)
+
+	w = serve(http.MethodPost, "/jobs", `{"service": "iot", "devices": ["mac:112233445566"], "command": {"command": "DELETE_ROW", "row": "Device.T.1."}}`)
+	assert.Equal(http.StatusBadRequest, w.Code)
+	assert.JSONEq(`{"message": "unsupported Service"}`, w.Body.String())
+
+	w = serve(http.MethodPost, "/jobs", `{"service": "config", "device": "mac:112233445566"}`)
+	assert.Equal(http.StatusBadRequest, w.Code)
+
+	w = serve(http.MethodPost, "/jobs", `{"service": "config", "devices": ["mac:112233445566"], "command": {"command": "ADD_ROW", "table": "Device.T.", "row": {"Port": "80"}}}`)
+	assert.Equal(http.StatusBadRequest, w.Code)
+	assert.JSONEq(`{"message": "column 'Port' is not in the schema of table 'Device.T.'"}`, w.Body.String())
+
+	w = serve(http.MethodGet, "/jobs", "")
+	require.Equal(http.StatusOK, w.Code)
+	var jobs jobsResponse
+	require.NoError(json.Unmarshal(w.Body.Bytes(), &jobs))
+	require.Len(jobs.Jobs, 1)
+	assert.Equal(job.ID, jobs.Jobs[0].ID)
+
+	w = serve(http.MethodGet, "/jobs/"+job.ID, "")
+	assert.Equal(http.StatusOK, w.Code)
+
+	w = serve(http.MethodDelete, "/jobs/"+job.ID, "")
+	require.Equal(http.StatusOK, w.Code)
+	require.NoError(json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(StatusCanceled, job.Status)
+
+	w = serve(http.MethodDelete, "/jobs/"+job.ID, "")
+	assert.Equal(http.StatusConflict, w.Code)
+
+	w = serve(http.MethodGet, "/jobs/unknown", "")
+	assert.Equal(http.StatusNotFound, w.Code)
+}
+
+// testToken is a token with partners.
+type testToken struct {
+	principal  string
+	partnerIDs []string
+}
+
+func (t testToken) Principal() string { return t.principal }
+
+func (t testToken) Get(key string) (interface{}, bool) {
+	if key == "partner-id" && t.partnerIDs != nil {
+		return t.partnerIDs, true
+	}
+	return nil, false
+}
+
+func TestConfigHandlerScope(t *testing.T) {
+	// the test caller's token is taken from the X-Test-Principal and
+	// X-Test-Partner headers
+	authenticate := alice.New(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := testToken{principal: r.Header.Get("X-Test-Principal"), partnerIDs: r.Header.Values("X-Test-Partner")}
+			next.ServeHTTP(w, r.WithContext(bascule.WithToken(r.Context(), token)))
+		})
+	})
+
+	router := mux.NewRouter()
+	ConfigHandler(&Options{
+		S:                           newTestScheduler(t, &testService{}),
+		APIRouter:                   router,
+		Authenticate:                &authenticate,
+		Log:                         zap.NewNop(),
+		ValidServices:               func() []string { return []string{"config"} },
+		ReducedLoggingResponseCodes: func() []int { return nil },
+		BearerFingerprint:           func() transaction.FingerprintConfig { return transaction.FingerprintConfig{} },
+	})
+
+	serve := func(method, path, principal string, partnerIDs ...string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "http://localhost"+path, bytes.NewBufferString(`{"service": "config", "devices": ["mac:112233445566"], "command": {"command": "DELETE_ROW", "row": "Device.T.1."}, "at": "2026-11-02T02:00:00Z"}`))
+		r.Header.Set("X-Test-Principal", principal)
+		for _, partnerID := range partnerIDs {
+			r.Header.Add("X-Test-Partner", partnerID)
+		}
+		// partner headers are never trusted
+		r.Header.Set("X-Xmidt-Partner-Id", "other")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve(http.MethodPost, "/jobs", "alice", "comcast")
+	require.Equal(t, http.StatusCreated, w.Code)
+	var job Job
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(t, "alice", job.Owner)
+	assert.Equal(t, []string{"comcast"}, job.PartnerIDs)
+
+	tests := []struct {
+		description string
+		principal   string
+		partnerIDs  []string
+		allowed     bool
+	}{
+		{description: "owner", principal: "alice", allowed: true},
+		{description: "same partner", principal: "bob", partnerIDs: []string{"comcast", "sky"}, allowed: true},
+		{description: "other partner", principal: "bob", partnerIDs: []string{"other"}},
+		{description: "no partner", principal: "bob"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			w := serve(http.MethodGet, "/jobs", tc.principal, tc.partnerIDs...)
+			var jobs jobsResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &jobs))
+			assert.Equal(tc.allowed, len(jobs.Jobs) == 1)
+
+			expectedCode := http.StatusNotFound
+			if tc.allowed {
+				expectedCode = http.StatusOK
+			}
+			assert.Equal(expectedCode, serve(http.MethodGet, "/jobs/"+job.ID, tc.principal, tc.partnerIDs...).Code)
+			if !tc.allowed {
+				assert.Equal(http.StatusNotFound, serve(http.MethodDelete, "/jobs/"+job.ID, tc.principal, tc.partnerIDs...).Code)
+			}
+		})
+	}
+}
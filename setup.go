@@ -16,6 +16,16 @@ func setupFlagSet(fs *pflag.FlagSet) {
 	fs.StringP("file", "f", "", "the configuration file to use.  Overrides the search path.")
 	fs.BoolP("debug", "d", false, "enables debug logging.  Overrides configuration.")
 	fs.BoolP("version", "v", false, "print version and exit")
+	fs.Bool("validate", false, "validate the configuration, print any problems and exit")
+}
+
+// newViper returns a viper instance with tr1d1um's defaults.
+func newViper() *viper.Viper {
+	v := viper.New()
+	for k, va := range defaults {
+		v.SetDefault(k, va)
+	}
+	return v
 }
 
 func setup(args []string) (*viper.Viper, *zap.Logger, *pflag.FlagSet, error) {
@@ -26,11 +36,7 @@ func setup(args []string) (*viper.Viper, *zap.Logger, *pflag.FlagSet, error) {
 		return nil, nil, fs, fmt.Errorf("failed to create parse args: %w", err)
 	}
 
-	v := viper.New()
-	for k, va := range defaults {
-		v.SetDefault(k, va)
-	}
-
+	v := newViper()
 	if file, _ := fs.GetString("file"); len(file) > 0 {
 		v.SetConfigFile(file)
 		err = v.ReadInConfig()
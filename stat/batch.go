@@ -0,0 +1,212 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package stat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/xmidt-org/candlelight"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+// Defaults for BatchConfig.
+const (
+	DefaultBatchMaxDevices  = 100
+	DefaultBatchConcurrency = 10
+)
+
+// maxBatchRequestSize is the maximum size, in bytes, of batch stat requests.
+const maxBatchRequestSize = 1024 * 1024
+
+// Batch stat errors
+var (
+	ErrInvalidBatch   = transaction.NewBadRequestError(errors.New("batch stat request is invalid, expected {\"devices\": [...]}"))
+	ErrMissingDevices = transaction.NewBadRequestError(errors.New("at least one device is required"))
+	ErrTooManyDevices = transaction.NewBadRequestError(errors.New("too many devices"))
+)
+
+// BatchConfig configures the batch stat endpoint, which requests the
+// statistics of several devices at once and summarizes them.
+type BatchConfig struct {
+	// MaxDevices is the maximum number of devices of a request.
+	// (Optional) Defaults to DefaultBatchMaxDevices.
+	MaxDevices int
+
+	// Concurrency is the maximum number of stat requests in flight for a
+	// request.
+	// (Optional) Defaults to DefaultBatchConcurrency.
+	Concurrency int
+}
+
+func (c BatchConfig) withDefaults() BatchConfig {
+	if c.MaxDevices <= 0 {
+		c.MaxDevices = DefaultBatchMaxDevices
+	}
+	if c.Concurrency <= 0 {
+		c.Concurrency = DefaultBatchConcurrency
+	}
+	return c
+}
+
+type batchRequest struct {
+	DeviceIDs       []string
+	AuthHeaderValue string
+}
+
+// batchDevice is the stat outcome of a device. Devices XMiDT doesn't know
+// are offline, other failures are reported as errors.
+type batchDevice struct {
+	Online      bool       `json:"online"`
+	StatusCode  int        `json:"statusCode"`
+	ConnectedAt *time.Time `json:"connectedAt,omitempty"`
+	UpTime      string     `json:"upTime,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+type batchResponse struct {
+	Online  int                    `json:"online"`
+	Offline int                    `json:"offline"`
+	Errors  int                    `json:"errors"`
+	Devices map[string]batchDevice `json:"devices"`
+}
+
+func (r *batchResponse) add(deviceID string, d batchDevice) {
+	switch {
+	case d.Error != "":
+		r.Errors++
+	case d.Online:
+		r.Online++
+	default:
+		r.Offline++
+	}
+	r.Devices[deviceID] = d
+}
+
+// decodeBatchRequest returns a decoder of batch stat requests of at most
+// maxDevices devices.
+func decodeBatchRequest(maxDevices int) func(context.Context, *http.Request) (interface{}, error) {
+	return func(_ context.Context, r *http.Request) (interface{}, error) {
+		var body struct {
+			Devices []string `json:"devices"`
+		}
+
+		dec := json.NewDecoder(io.LimitReader(r.Body, maxBatchRequestSize))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&body); err != nil {
+			return nil, ErrInvalidBatch
+		}
+
+		if len(body.Devices) == 0 {
+			return nil, ErrMissingDevices
+		}
+
+		deviceIDs := make([]string, 0, len(body.Devices))
+		for _, id := range body.Devices {
+			deviceID, err := wrp.ParseDeviceID(id)
+			if err != nil {
+				return nil, transaction.NewBadRequestError(err)
+			}
+			if !slices.Contains(deviceIDs, string(deviceID)) {
+				deviceIDs = append(deviceIDs, string(deviceID))
+			}
+		}
+
+		if len(deviceIDs) > maxDevices {
+			return nil, ErrTooManyDevices
+		}
+
+		return &batchRequest{
+			DeviceIDs:       deviceIDs,
+			AuthHeaderValue: r.Header.Get(authHeaderKey),
+		}, nil
+	}
+}
+
+// makeBatchEndpoint requests the statistics of every device of a batch
+// request, at most concurrency at a time.
+func makeBatchEndpoint(s Service, concurrency int) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*batchRequest)
+
+		var (
+			mu   sync.Mutex
+			wg   sync.WaitGroup
+			sem  = make(chan struct{}, concurrency)
+			resp = &batchResponse{Devices: make(map[string]batchDevice, len(req.DeviceIDs))}
+		)
+		for _, deviceID := range req.DeviceIDs {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				d := summarizeStat(s.RequestStat(ctx, req.AuthHeaderValue, deviceID))
+				mu.Lock()
+				resp.add(deviceID, d)
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		return resp, nil
+	}
+}
+
+// summarizeStat returns the stat outcome of a device from the response of
+// XMiDT.
+func summarizeStat(resp *transaction.XmidtResponse, err error) batchDevice {
+	if err != nil {
+		var ce transaction.CodedError
+		if errors.As(err, &ce) {
+			return batchDevice{StatusCode: ce.StatusCode(), Error: err.Error()}
+		}
+		return batchDevice{StatusCode: http.StatusInternalServerError, Error: transaction.ErrTr1d1umInternal.Error()}
+	}
+
+	switch resp.Code {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return batchDevice{StatusCode: resp.Code}
+	default:
+		return batchDevice{StatusCode: resp.Code, Error: http.StatusText(resp.Code)}
+	}
+
+	stat, err := ParseStat(resp.Body)
+	if err != nil {
+		return batchDevice{StatusCode: ErrInvalidStat.StatusCode(), Error: err.Error()}
+	}
+
+	d := batchDevice{
+		Online:     true,
+		StatusCode: resp.Code,
+	}
+	if stat.Statistics.UpTime != 0 {
+		d.UpTime = time.Duration(stat.Statistics.UpTime).String()
+	}
+	if !stat.Statistics.ConnectedAt.IsZero() {
+		d.ConnectedAt = &stat.Statistics.ConnectedAt
+	}
+	return d
+}
+
+func encodeBatchResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	var ctxKeyReqTID string
+	if c := ctx.Value(transaction.ContextKeyRequestTID); c != nil {
+		ctxKeyReqTID = c.(string)
+	}
+
+	w.Header().Set(contentTypeHeaderKey, "application/json")
+	w.Header().Set(candlelight.HeaderWPATIDKeyName, ctxKeyReqTID)
+	return json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package stat
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"go.uber.org/zap"
+)
+
+const testStatBody = `{"id": "mac:112233445566", "pending": 0, "statistics": {"bytesSent": 10, "messagesSent": 1, "bytesReceived": 20, "messagesReceived": 2, "duplications": 0, "connectedAt": "2026-11-01T02:00:00Z", "upTime": "1h0m0s"}}`
+
+func TestDecodeBatchRequest(t *testing.T) {
+	tests := []struct {
+		description string
+		body        string
+		expected    []string
+		expectedErr error
+	}{
+		{
+			description: "valid",
+			body:        `{"devices": ["MAC:11-22-33-44-55-66", "mac:112233445566", "mac:aabbccddeeff"]}`,
+			expected:    []string{"mac:112233445566", "mac:aabbccddeeff"},
+		},
+		{
+			description: "invalid body",
+			body:        `{"device": "mac:112233445566"}`,
+			expectedErr: ErrInvalidBatch,
+		},
+		{
+			description: "no devices",
+			body:        `{"devices": []}`,
+			expectedErr: ErrMissingDevices,
+		},
+		{
+			description: "too many devices",
+			body:        `{"devices": ["mac:112233445566", "mac:aabbccddeeff", "mac:aabbccddee00"]}`,
+			expectedErr: ErrTooManyDevices,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "http://localhost/devices/stat", bytes.NewBufferString(tc.body))
+			r.Header.Set("Authorization", "a0")
+
+			req, err := decodeBatchRequest(2)(context.Background(), r)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, &batchRequest{DeviceIDs: tc.expected, AuthHeaderValue: "a0"}, req)
+		})
+	}
+
+	t.Run("invalid device", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "http://localhost/devices/stat", bytes.NewBufferString(`{"devices": ["nope"]}`))
+		_, err := decodeBatchRequest(2)(context.Background(), r)
+
+		var ce transaction.CodedError
+		require.ErrorAs(t, err, &ce)
+		assert.Equal(t, http.StatusBadRequest, ce.StatusCode())
+	})
+}
+
+func TestBatchHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	s := new(MockService)
+	s.On("RequestStat", mock.Anything, "a0", "mac:112233445566").Return(&transaction.XmidtResponse{Code: http.StatusOK, Body: []byte(testStatBody)}, nil)
+	s.On("RequestStat", mock.Anything, "a0", "mac:aabbccddeeff").Return(&transaction.XmidtResponse{Code: http.StatusNotFound, Body: []byte{}}, nil)
+	s.On("RequestStat", mock.Anything, "a0", "mac:aabbccddee00").Return(&transaction.XmidtResponse{Code: http.StatusOK, Body: []byte("nope")}, nil)
+	s.On("RequestStat", mock.Anything, "a0", "mac:aabbccddee01").Return(nil, transaction.NewCodedError(errors.New("request timed out"), http.StatusServiceUnavailable))
+	s.On("RequestStat", mock.Anything, "a0", "mac:aabbccddee02").Return(nil, errors.New("connection refused"))
+
+	router := mux.NewRouter()
+	ConfigHandler(&Options{
+		S:                           s,
+		APIRouter:                   router,
+		Authenticate:                &alice.Chain{},
+		Log:                         zap.NewNop(),
+		ReducedLoggingResponseCodes: func() []int { return nil },
+		BearerFingerprint:           func() transaction.FingerprintConfig { return transaction.FingerprintConfig{} },
+		Batch:                       BatchConfig{Concurrency: 2},
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "http://localhost/devices/stat", bytes.NewBufferString(
+		`{"devices": ["mac:112233445566", "mac:aabbccddeeff", "mac:aabbccddee00", "mac:aabbccddee01", "mac:aabbccddee02"]}`,
+	))
+	r.Header.Set("Authorization", "a0")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(http.StatusOK, w.Code)
+	assert.JSONEq(`{
+		"online": 1,
+		"offline": 1,
+		"errors": 3,
+		"devices": {
+			"mac:112233445566": {"online": true, "statusCode": 200, "connectedAt": "2026-11-01T02:00:00Z", "upTime": "1h0m0s"},
+			"mac:aabbccddeeff": {"online": false, "statusCode": 404},
+			"mac:aabbccddee00": {"online": false, "statusCode": 502, "error": "invalid stat response"},
+			"mac:aabbccddee01": {"online": false, "statusCode": 503, "error": "request timed out"},
+			"mac:aabbccddee02": {"online": false, "statusCode": 500, "error": "oops! Something unexpected went wrong in this service"}
+		}
+	}`, w.Body.String())
+	s.AssertExpectations(t)
+}
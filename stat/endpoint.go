@@ -5,6 +5,7 @@ package stat
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/go-kit/kit/endpoint"
 )
@@ -14,9 +15,15 @@ type statRequest struct {
 	AuthHeaderValue string
 }
 
+// makeStatEndpoint requests the stat of a device, failing with
+// ErrDeviceOffline for devices XMiDT doesn't have a connection to.
 func makeStatEndpoint(s Service) endpoint.Endpoint {
 	return func(ctx context.Context, r interface{}) (interface{}, error) {
 		statReq := (r).(*statRequest)
-		return s.RequestStat(ctx, statReq.AuthHeaderValue, statReq.DeviceID)
+		resp, err := s.RequestStat(ctx, statReq.AuthHeaderValue, statReq.DeviceID)
+		if err == nil && resp != nil && resp.Code == http.StatusNotFound {
+			return nil, ErrDeviceOffline
+		}
+		return resp, err
 	}
 }
@@ -5,7 +5,11 @@ package stat
 
 import (
 	"context"
+	"net/http"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/tr1d1um/transaction"
 )
 
 func TestMakeStatEndpoint(t *testing.T) {
@@ -22,3 +26,13 @@ func TestMakeStatEndpoint(t *testing.T) {
 	endpoint(context.TODO(), sr)
 	s.AssertExpectations(t)
 }
+
+func TestMakeStatEndpointOffline(t *testing.T) {
+	s := new(MockService)
+	s.On("RequestStat", context.TODO(), "a0", "mac:112233445566").Return(&transaction.XmidtResponse{Code: http.StatusNotFound, Body: []byte("not found")}, nil)
+
+	resp, err := makeStatEndpoint(s)(context.TODO(), &statRequest{DeviceID: "mac:112233445566", AuthHeaderValue: "a0"})
+	assert.Nil(t, resp)
+	assert.Equal(t, ErrDeviceOffline, err)
+	s.AssertExpectations(t)
+}
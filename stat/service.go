@@ -19,8 +19,12 @@ type Service interface {
 // NewService constructs a new stat service instance given some options.
 func NewService(o *ServiceOptions) Service {
 	return &service{
-		transactor:   o.HTTPTransactor,
-		authAcquirer: o.AuthAcquirer,
+		transactor: o.HTTPTransactor,
+		auth: transaction.OutboundAuth{
+			Policy:    o.AuthPolicy,
+			Acquirer:  o.AuthAcquirer,
+			Exchanger: o.TokenExchanger,
+		},
 		xmidtStatURL: o.XmidtStatURL,
 	}
 }
@@ -36,6 +40,15 @@ type ServiceOptions struct {
 	//(Optional)
 	AuthAcquirer transaction.AuthAcquirer
 
+	//AuthPolicy chooses whether stat requests forward the caller's credentials,
+	//use the AuthAcquirer or use a token from the TokenExchanger.
+	//(Optional) By default, the AuthAcquirer is used if set.
+	AuthPolicy transaction.AuthPolicy
+
+	//TokenExchanger mints downstream tokens carrying the caller's identity.
+	//(Optional)
+	TokenExchanger transaction.TokenExchanger
+
 	//HTTPTransactor is the component that's responsible to make the HTTP
 	//request to the XMiDT API and return only data we care about.
 	HTTPTransactor transaction.T
@@ -44,7 +57,7 @@ type ServiceOptions struct {
 type service struct {
 	transactor transaction.T
 
-	authAcquirer transaction.AuthAcquirer
+	auth transaction.OutboundAuth
 
 	xmidtStatURL string
 }
@@ -57,11 +70,9 @@ func (s *service) RequestStat(ctx context.Context, authHeaderValue, deviceID str
 		return nil, err
 	}
 
-	if s.authAcquirer != nil {
-		authHeaderValue, err = s.authAcquirer.Acquire()
-		if err != nil {
-			return nil, err
-		}
+	authHeaderValue, err = s.auth.Authorization(ctx, transaction.APIStat, "", authHeaderValue)
+	if err != nil {
+		return nil, err
 	}
 
 	r.Header.Set("Authorization", authHeaderValue)
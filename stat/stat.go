@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package stat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/xmidt-org/tr1d1um/transaction"
+)
+
+// Stat errors
+var (
+	ErrDeviceOffline = transaction.NewCodedError(errors.New("device is offline"), http.StatusNotFound)
+	ErrInvalidStat   = transaction.NewCodedError(errors.New("invalid stat response"), http.StatusBadGateway)
+)
+
+// DeviceStat is the stat body XMiDT returns for a connected device.
+type DeviceStat struct {
+	ID         string     `json:"id"`
+	Pending    int        `json:"pending"`
+	Statistics Statistics `json:"statistics"`
+}
+
+// Statistics are the connection statistics of a device.
+type Statistics struct {
+	BytesSent        int64     `json:"bytesSent"`
+	MessagesSent     int64     `json:"messagesSent"`
+	BytesReceived    int64     `json:"bytesReceived"`
+	MessagesReceived int64     `json:"messagesReceived"`
+	Duplications     int64     `json:"duplications"`
+	ConnectedAt      time.Time `json:"connectedAt"`
+	UpTime           Duration  `json:"upTime"`
+
+	// Reason is why the device last disconnected, when XMiDT reports it.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Duration is a time.Duration written as a string such as "1h2m3s".
+type Duration time.Duration
+
+// UnmarshalJSON reads a duration string, an empty string being no duration.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	if s == "" {
+		*d = 0
+		return nil
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON writes the duration as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// ParseStat parses the stat body of a device.
+func ParseStat(body []byte) (DeviceStat, error) {
+	var stat DeviceStat
+	if err := json.Unmarshal(body, &stat); err != nil {
+		return DeviceStat{}, ErrInvalidStat
+	}
+	return stat, nil
+}
+
+// Summary is the stat of a device with derived fields, returned for stat
+// requests with format=summary.
+type Summary struct {
+	ID               string    `json:"id"`
+	Online           bool      `json:"online"`
+	ConnectedAt      time.Time `json:"connectedAt"`
+	UpTime           string    `json:"upTime"`
+	UpTimeSeconds    int64     `json:"upTimeSeconds"`
+	BytesSent        int64     `json:"bytesSent"`
+	MessagesSent     int64     `json:"messagesSent"`
+	BytesReceived    int64     `json:"bytesReceived"`
+	MessagesReceived int64     `json:"messagesReceived"`
+	Reason           string    `json:"reason,omitempty"`
+}
+
+// Summarize returns the summary of the stat of a connected device. The uptime
+// is computed from the connection time when XMiDT doesn't report it.
+func (s DeviceStat) Summarize(now time.Time) Summary {
+	upTime := time.Duration(s.Statistics.UpTime)
+	if upTime == 0 && !s.Statistics.ConnectedAt.IsZero() {
+		upTime = now.Sub(s.Statistics.ConnectedAt)
+	}
+	upTime = upTime.Truncate(time.Second)
+
+	return Summary{
+		ID:               s.ID,
+		Online:           true,
+		ConnectedAt:      s.Statistics.ConnectedAt,
+		UpTime:           humanizeDuration(upTime),
+		UpTimeSeconds:    int64(upTime / time.Second),
+		BytesSent:        s.Statistics.BytesSent,
+		MessagesSent:     s.Statistics.MessagesSent,
+		BytesReceived:    s.Statistics.BytesReceived,
+		MessagesReceived: s.Statistics.MessagesReceived,
+		Reason:           s.Statistics.Reason,
+	}
+}
+
+// humanizeDuration writes d in days, hours, minutes and seconds, leaving out
+// the units that are zero, e.g. "2d 3h 15s".
+func humanizeDuration(d time.Duration) string {
+	if d < time.Second {
+		return "0s"
+	}
+
+	var parts []string
+	for _, unit := range []struct {
+		d    time.Duration
+		name string
+	}{
+		{24 * time.Hour, "d"},
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	} {
+		if n := d / unit.d; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", n, unit.name))
+			d -= n * unit.d
+		}
+	}
+	return strings.Join(parts, " ")
+}
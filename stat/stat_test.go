@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package stat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStat(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		stat, err := ParseStat([]byte(testStatBody))
+		require.NoError(t, err)
+		assert.Equal(t, DeviceStat{
+			ID: "mac:112233445566",
+			Statistics: Statistics{
+				BytesSent:        10,
+				MessagesSent:     1,
+				BytesReceived:    20,
+				MessagesReceived: 2,
+				ConnectedAt:      time.Date(2026, 11, 1, 2, 0, 0, 0, time.UTC),
+				UpTime:           Duration(time.Hour),
+			},
+		}, stat)
+	})
+
+	for _, body := range []string{"nope", `{"statistics": {"upTime": "forever"}}`, `{"statistics": {"upTime": 10}}`} {
+		t.Run(body, func(t *testing.T) {
+			_, err := ParseStat([]byte(body))
+			assert.Equal(t, ErrInvalidStat, err)
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	connectedAt := time.Date(2026, 11, 1, 2, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		description    string
+		upTime         time.Duration
+		expectedUpTime string
+		expectedSecs   int64
+	}{
+		{
+			description:    "reported uptime",
+			upTime:         26*time.Hour + 3*time.Minute + 4500*time.Millisecond,
+			expectedUpTime: "1d 2h 3m 4s",
+			expectedSecs:   93784,
+		},
+		{
+			description:    "computed uptime",
+			expectedUpTime: "2d 15s",
+			expectedSecs:   172815,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			stat := DeviceStat{
+				ID: "mac:112233445566",
+				Statistics: Statistics{
+					BytesSent:   10,
+					ConnectedAt: connectedAt,
+					UpTime:      Duration(tc.upTime),
+					Reason:      "readerror",
+				},
+			}
+
+			assert.Equal(t, Summary{
+				ID:            "mac:112233445566",
+				Online:        true,
+				ConnectedAt:   connectedAt,
+				UpTime:        tc.expectedUpTime,
+				UpTimeSeconds: tc.expectedSecs,
+				BytesSent:     10,
+				Reason:        "readerror",
+			}, stat.Summarize(connectedAt.Add(48*time.Hour+15*time.Second)))
+		})
+	}
+}
+
+func TestHumanizeDuration(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("0s", humanizeDuration(0))
+	assert.Equal("0s", humanizeDuration(time.Millisecond))
+	assert.Equal("1m", humanizeDuration(time.Minute))
+	assert.Equal("1h 1s", humanizeDuration(time.Hour+time.Second))
+	assert.Equal("3d", humanizeDuration(72*time.Hour))
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/xmidt-org/candlelight"
 	"github.com/xmidt-org/sallust"
@@ -25,8 +26,23 @@ const (
 	authHeaderKey        = "Authorization"
 )
 
+// statFormatParam is the query parameter choosing the format of stat
+// responses. With format=summary, the stat of a connected device is returned
+// as a Summary. Otherwise, the stat body XMiDT returned is forwarded.
+const (
+	statFormatParam   = "format"
+	statFormatSummary = "summary"
+)
+
+type contextKey int
+
+const contextKeySummary contextKey = iota
+
 var (
 	errResponseIsNil = errors.New("response is nil")
+
+	// ErrInvalidFormat is returned for unsupported stat response formats.
+	ErrInvalidFormat = transaction.NewBadRequestError(errors.New("unsupported format, expected 'summary'"))
 )
 
 // Options wraps the properties needed to set up the stat server
@@ -37,30 +53,74 @@ type Options struct {
 	APIRouter                   *mux.Router
 	Authenticate                *alice.Chain
 	Log                         *zap.Logger
-	ReducedLoggingResponseCodes []int
-	BearerFingerprint           transaction.FingerprintConfig
+	ReducedLoggingResponseCodes func() []int
+	BearerFingerprint           func() transaction.FingerprintConfig
+
+	//Timeouts bounds the timeouts clients may request with the X-Webpa-Timeout header.
+	//The limits of the "stat" service apply.
+	Timeouts transaction.TimeoutPolicy
+
+	//Batch configures the batch stat endpoint.
+	Batch BatchConfig
+
+	//Watcher serves the watch endpoint.
+	Watcher *Watcher
 }
 
 // ConfigHandler sets up the server that powers the stat service
 // That is, it configures the mux paths to access the service
+// POST /devices/stat summarizes the statistics of a list of devices and
+// GET /device/{deviceid}/stat/watch reports the changes of the presence of a
+// device.
 func ConfigHandler(c *Options) {
 	opts := []kithttp.ServerOption{
+		kithttp.ServerBefore(transaction.CaptureRequestTimeout(c.Timeouts, statService), captureFormat),
 		kithttp.ServerErrorEncoder(transaction.ErrorLogEncoder(sallust.Get, encodeError)),
-		kithttp.ServerFinalizer(transaction.Log(c.ReducedLoggingResponseCodes)),
+		kithttp.ServerFinalizer(transaction.LogFunc(c.ReducedLoggingResponseCodes)),
 	}
 
 	statHandler := kithttp.NewServer(
 		makeStatEndpoint(c.S),
-		decodeRequest,
+		transaction.DecodeRequestTimeout(decodeRequest),
 		encodeResponse,
 		opts...,
 	)
 
-	c.APIRouter.Handle("/device/{deviceid}/stat", c.Authenticate.Then(candlelight.EchoFirstTraceNodeInfo(candlelight.Tracing{}.Propagator(), false)(transaction.Welcome(c.BearerFingerprint)(statHandler)))).
+	batch := c.Batch.withDefaults()
+	batchHandler := kithttp.NewServer(
+		makeBatchEndpoint(c.S, batch.Concurrency),
+		transaction.DecodeRequestTimeout(decodeBatchRequest(batch.MaxDevices)),
+		encodeBatchResponse,
+		opts...,
+	)
+
+	c.APIRouter.Handle("/device/{deviceid}/stat", c.Authenticate.Then(candlelight.EchoFirstTraceNodeInfo(candlelight.Tracing{}.Propagator(), false)(transaction.WelcomeFunc(c.BearerFingerprint)(statHandler)))).
 		Methods(http.MethodGet)
+	c.APIRouter.Handle("/device/{deviceid}/stat/watch", c.Authenticate.Then(candlelight.EchoFirstTraceNodeInfo(candlelight.Tracing{}.Propagator(), false)(transaction.WelcomeFunc(c.BearerFingerprint)(&watchHandler{watcher: c.Watcher})))).
+		Methods(http.MethodGet)
+	c.APIRouter.Handle("/devices/stat", c.Authenticate.Then(candlelight.EchoFirstTraceNodeInfo(candlelight.Tracing{}.Propagator(), false)(transaction.WelcomeFunc(c.BearerFingerprint)(batchHandler)))).
+		Methods(http.MethodPost)
+}
+
+// statService returns the service name whose timeout limits apply to stat requests.
+func statService(*http.Request) string {
+	return "stat"
+}
+
+// captureFormat marks requests asking for the summary format in their
+// context, for encodeResponse.
+func captureFormat(ctx context.Context, r *http.Request) context.Context {
+	if r.URL.Query().Get(statFormatParam) == statFormatSummary {
+		return context.WithValue(ctx, contextKeySummary, true)
+	}
+	return ctx
 }
 
 func decodeRequest(_ context.Context, r *http.Request) (req interface{}, err error) {
+	if format := r.URL.Query().Get(statFormatParam); format != "" && format != statFormatSummary {
+		return nil, ErrInvalidFormat
+	}
+
 	var deviceID wrp.DeviceID
 	if deviceID, err = wrp.ParseDeviceID(mux.Vars(r)["deviceid"]); err == nil {
 		req = &statRequest{
@@ -99,7 +159,8 @@ func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
 	})
 }
 
-// encodeResponse simply forwards the response Tr1d1um got from the XMiDT API
+// encodeResponse simply forwards the response Tr1d1um got from the XMiDT API,
+// or its Summary for successful requests with format=summary.
 // TODO: What about if XMiDT cluster reports 500. There would be ambiguity
 // about which machine is actually having the error (Tr1d1um or the Xmidt API)
 // do we care to make that distinction?
@@ -111,6 +172,17 @@ func encodeResponse(ctx context.Context, w http.ResponseWriter, response interfa
 		return
 	}
 
+	body := resp.Body
+	if summary, _ := ctx.Value(contextKeySummary).(bool); summary && resp.Code == http.StatusOK {
+		var stat DeviceStat
+		if stat, err = ParseStat(resp.Body); err != nil {
+			return
+		}
+		if body, err = json.Marshal(stat.Summarize(time.Now())); err != nil {
+			return
+		}
+	}
+
 	if resp.Code == http.StatusOK {
 		w.Header().Set("Content-Type", "application/json")
 	} else {
@@ -129,6 +201,6 @@ func encodeResponse(ctx context.Context, w http.ResponseWriter, response interfa
 
 	w.WriteHeader(resp.Code)
 
-	_, err = w.Write(resp.Body)
+	_, err = w.Write(body)
 	return
 }
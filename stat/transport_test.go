@@ -157,3 +157,57 @@ func TestEncodeResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeRequestFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	r := httptest.NewRequest(http.MethodGet, "http://localhost:8090/api/stat?format=xml", nil)
+	r = mux.SetURLVars(r, map[string]string{"deviceid": "mac:112233445566"})
+	_, err := decodeRequest(ctxTID, r)
+	assert.Equal(ErrInvalidFormat, err)
+
+	r = httptest.NewRequest(http.MethodGet, "http://localhost:8090/api/stat?format=summary", nil)
+	r = mux.SetURLVars(r, map[string]string{"deviceid": "mac:112233445566"})
+	_, err = decodeRequest(ctxTID, r)
+	assert.NoError(err)
+
+	ctx := captureFormat(ctxTID, r)
+	assert.Equal(true, ctx.Value(contextKeySummary))
+}
+
+func TestEncodeSummaryResponse(t *testing.T) {
+	ctx := context.WithValue(ctxTID, contextKeySummary, true)
+
+	t.Run("online", func(t *testing.T) {
+		assert := assert.New(t)
+
+		w := httptest.NewRecorder()
+		err := encodeResponse(ctx, w, &transaction.XmidtResponse{Code: http.StatusOK, Body: []byte(testStatBody)})
+		assert.NoError(err)
+		assert.Equal(http.StatusOK, w.Code)
+		assert.JSONEq(`{
+			"id": "mac:112233445566",
+			"online": true,
+			"connectedAt": "2026-11-01T02:00:00Z",
+			"upTime": "1h",
+			"upTimeSeconds": 3600,
+			"bytesSent": 10,
+			"messagesSent": 1,
+			"bytesReceived": 20,
+			"messagesReceived": 2
+		}`, w.Body.String())
+	})
+
+	t.Run("invalid stat", func(t *testing.T) {
+		err := encodeResponse(ctx, httptest.NewRecorder(), &transaction.XmidtResponse{Code: http.StatusOK, Body: []byte("nope")})
+		assert.Equal(t, ErrInvalidStat, err)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		err := encodeResponse(ctx, w, &transaction.XmidtResponse{Code: http.StatusServiceUnavailable, Body: []byte("unavailable")})
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "unavailable", w.Body.String())
+	})
+}
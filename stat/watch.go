@@ -0,0 +1,401 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package stat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xmidt-org/candlelight"
+	"github.com/xmidt-org/tr1d1um/transaction"
+)
+
+// Defaults for WatchConfig.
+const (
+	DefaultWatchInterval        = 10 * time.Second
+	DefaultWatchIdleTimeout     = time.Minute
+	DefaultWatchLongPollTimeout = 30 * time.Second
+	DefaultWatchMaxDevices      = 1000
+)
+
+// mediaTypeEventStream is the media type of server-sent events.
+const mediaTypeEventStream = "text/event-stream"
+
+// Watch errors
+var (
+	ErrTooManyWatches = transaction.NewCodedError(errors.New("too many devices are watched, try again later"), http.StatusServiceUnavailable)
+	ErrNoPresence     = transaction.NewCodedError(errors.New("the device presence isn't known yet, try again"), http.StatusGatewayTimeout)
+	ErrWatchStopped   = transaction.NewCodedError(errors.New("watches are stopped"), http.StatusServiceUnavailable)
+)
+
+// WatchConfig configures the watch endpoint, which reports the changes of the
+// presence of a device.
+type WatchConfig struct {
+	// Interval is how often the stat of a watched device is requested.
+	// (Optional) Defaults to DefaultWatchInterval.
+	Interval time.Duration
+
+	// IdleTimeout is how long the stat of a device keeps being requested
+	// after its last watcher left, for long-poll watchers to come back.
+	// (Optional) Defaults to DefaultWatchIdleTimeout.
+	IdleTimeout time.Duration
+
+	// LongPollTimeout is how long a long-poll request waits for a change.
+	// (Optional) Defaults to DefaultWatchLongPollTimeout.
+	LongPollTimeout time.Duration
+
+	// MaxDevices is the maximum number of devices watched at once, a device
+	// watched with several credentials counting once per credentials.
+	// (Optional) Defaults to DefaultWatchMaxDevices.
+	MaxDevices int
+}
+
+func (c WatchConfig) withDefaults() WatchConfig {
+	if c.Interval <= 0 {
+		c.Interval = DefaultWatchInterval
+	}
+	if c.IdleTimeout <= 0 {
+		c.IdleTimeout = DefaultWatchIdleTimeout
+	}
+	if c.LongPollTimeout <= 0 {
+		c.LongPollTimeout = DefaultWatchLongPollTimeout
+	}
+	if c.MaxDevices <= 0 {
+		c.MaxDevices = DefaultWatchMaxDevices
+	}
+	return c
+}
+
+// presence is the state of a device reported to watchers. It leaves out the
+// fields changing on every stat request, such as the uptime.
+type presence struct {
+	ID string `json:"id"`
+	batchDevice
+}
+
+// Watcher requests the stat of watched devices, sharing a single poll per
+// device and credentials across their watchers. Watchers with different
+// credentials never share a poll, so that each of them only learns what
+// their own credentials allow.
+type Watcher struct {
+	config  WatchConfig
+	service Service
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu    sync.Mutex
+	polls map[pollKey]*poll
+}
+
+// NewWatcher returns a Watcher requesting the stat of devices with s. Its
+// polls run until Stop is called.
+func NewWatcher(c WatchConfig, s Service) *Watcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Watcher{
+		config:  c.withDefaults(),
+		service: s,
+		ctx:     ctx,
+		cancel:  cancel,
+		polls:   make(map[pollKey]*poll),
+	}
+}
+
+// Stop stops all polls, ending the requests of their watchers.
+func (w *Watcher) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.cancel()
+	for key, p := range w.polls {
+		if p.idle != nil {
+			p.idle.Stop()
+		}
+		delete(w.polls, key)
+	}
+}
+
+// pollKey identifies the poll of a device for some credentials.
+type pollKey struct {
+	deviceID        string
+	authHeaderValue string
+}
+
+// poll is the periodic stat request of a watched device.
+type poll struct {
+	key    pollKey
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// watchers and idle are guarded by the watcher's mutex.
+	watchers int
+	idle     *time.Timer
+
+	mu      sync.Mutex
+	data    []byte
+	etag    string
+	changed chan struct{}
+	err     error
+}
+
+// subscribe returns the poll of a device for the credentials of a watcher,
+// starting it if the device isn't watched with them yet.
+func (w *Watcher) subscribe(deviceID, authHeaderValue string) (*poll, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.ctx.Err() != nil {
+		return nil, ErrWatchStopped
+	}
+
+	key := pollKey{deviceID: deviceID, authHeaderValue: authHeaderValue}
+	p, ok := w.polls[key]
+	if !ok {
+		if len(w.polls) >= w.config.MaxDevices {
+			return nil, ErrTooManyWatches
+		}
+
+		ctx, cancel := context.WithCancel(w.ctx)
+		p = &poll{
+			key:     key,
+			ctx:     ctx,
+			cancel:  cancel,
+			changed: make(chan struct{}),
+		}
+		w.polls[key] = p
+		go w.run(p)
+	}
+
+	if p.idle != nil {
+		p.idle.Stop()
+		p.idle = nil
+	}
+	p.watchers++
+
+	return p, nil
+}
+
+// unsubscribe stops the poll of a device once it had no watchers for the
+// idle timeout.
+func (w *Watcher) unsubscribe(p *poll) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if p.watchers--; p.watchers > 0 {
+		return
+	}
+
+	p.idle = time.AfterFunc(w.config.IdleTimeout, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		if p.watchers == 0 && w.polls[p.key] == p {
+			delete(w.polls, p.key)
+			p.cancel()
+		}
+	})
+}
+
+func (w *Watcher) run(p *poll) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		d := summarizeStat(w.service.RequestStat(p.ctx, p.key.authHeaderValue, p.key.deviceID))
+		if p.ctx.Err() != nil {
+			return
+		}
+		if d.StatusCode == http.StatusUnauthorized || d.StatusCode == http.StatusForbidden {
+			w.reject(p, d.StatusCode)
+			return
+		}
+
+		d.UpTime = ""
+		p.update(presence{ID: p.key.deviceID, batchDevice: d})
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reject stops the poll of credentials the XMiDT cluster rejected, such as
+// expired or revoked tokens, ending the requests of its watchers with the
+// status code of the rejection.
+func (w *Watcher) reject(p *poll, code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.polls[p.key] == p {
+		delete(w.polls, p.key)
+	}
+	if p.idle != nil {
+		p.idle.Stop()
+	}
+
+	p.mu.Lock()
+	p.err = transaction.NewCodedError(errors.New("the credentials of the watch were rejected"), code)
+	p.mu.Unlock()
+	p.cancel()
+}
+
+// stopErr returns why the poll stopped.
+func (p *poll) stopErr() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+	return ErrWatchStopped
+}
+
+// update records the presence of the device, waking up the watchers if it
+// changed.
+func (p *poll) update(current presence) {
+	data, err := json.Marshal(current)
+	if err != nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if bytes.Equal(data, p.data) {
+		return
+	}
+
+	h := fnv.New64a()
+	h.Write(data)
+	p.data = data
+	p.etag = fmt.Sprintf("%x", h.Sum64())
+
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
+
+// current returns the latest presence of the device, nil until the first stat
+// request completed, its tag and a channel closed on its next change.
+func (p *poll) current() ([]byte, string, <-chan struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.data, p.etag, p.changed
+}
+
+// watchHandler serves the watch endpoint: clients accepting text/event-stream
+// get a presence event on every change, other clients long-poll, sending the
+// ETag of the presence they have in If-None-Match.
+type watchHandler struct {
+	watcher *Watcher
+}
+
+func (h *watchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeRequest(ctx, r)
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+	statReq := req.(*statRequest)
+
+	p, err := h.watcher.subscribe(statReq.DeviceID, statReq.AuthHeaderValue)
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+	defer h.watcher.unsubscribe(p)
+
+	var tid string
+	if c := ctx.Value(transaction.ContextKeyRequestTID); c != nil {
+		tid = c.(string)
+	}
+	w.Header().Set(candlelight.HeaderWPATIDKeyName, tid)
+
+	if strings.Contains(r.Header.Get("Accept"), mediaTypeEventStream) {
+		h.stream(ctx, w, r, p)
+		return
+	}
+	h.longPoll(ctx, w, r, p)
+}
+
+// stream writes a presence event on every change until the client leaves or
+// the poll stops.
+// Clients reconnecting with Last-Event-ID skip the presence they already have.
+func (h *watchHandler) stream(ctx context.Context, w http.ResponseWriter, r *http.Request, p *poll) {
+	w.Header().Set(contentTypeHeaderKey, mediaTypeEventStream)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	rc.Flush()
+
+	last := r.Header.Get("Last-Event-ID")
+	for {
+		data, etag, changed := p.current()
+		if data != nil && etag != last {
+			if _, err := fmt.Fprintf(w, "id: %s\nevent: presence\ndata: %s\n\n", etag, data); err != nil {
+				return
+			}
+			rc.Flush()
+			last = etag
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.ctx.Done():
+			return
+		case <-changed:
+		}
+	}
+}
+
+// longPoll responds with the presence of the device once it differs from the
+// one of If-None-Match, or with a 304 after the long-poll timeout.
+func (h *watchHandler) longPoll(ctx context.Context, w http.ResponseWriter, r *http.Request, p *poll) {
+	known := strings.Trim(strings.TrimPrefix(r.Header.Get("If-None-Match"), "W/"), `"`)
+
+	timeout := time.NewTimer(h.watcher.config.LongPollTimeout)
+	defer timeout.Stop()
+
+	for {
+		data, etag, changed := p.current()
+		if data != nil && etag != known {
+			w.Header().Set(contentTypeHeaderKey, "application/json")
+			w.Header().Set("ETag", `"`+etag+`"`)
+			w.WriteHeader(http.StatusOK)
+			w.Write(data)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.ctx.Done():
+			encodeError(ctx, p.stopErr(), w)
+			return
+		case <-timeout.C:
+			if data == nil {
+				encodeError(ctx, ErrNoPresence, w)
+				return
+			}
+			w.Header().Set("ETag", `"`+etag+`"`)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		case <-changed:
+		}
+	}
+}
@@ -0,0 +1,267 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package stat
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/tr1d1um/transaction"
+)
+
+// testService answers stat requests with its current response, counting them.
+type testService struct {
+	mu       sync.Mutex
+	resp     *transaction.XmidtResponse
+	requests int
+	auths    map[string]int
+}
+
+func (s *testService) RequestStat(_ context.Context, authHeaderValue, _ string) (*transaction.XmidtResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	if s.auths == nil {
+		s.auths = make(map[string]int)
+	}
+	s.auths[authHeaderValue]++
+	return s.resp, nil
+}
+
+func (s *testService) set(code int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resp = &transaction.XmidtResponse{Code: code, Body: []byte(body)}
+}
+
+func newTestWatcher(t *testing.T, s *testService) *Watcher {
+	s.set(http.StatusOK, testStatBody)
+	w := NewWatcher(WatchConfig{
+		Interval:        5 * time.Millisecond,
+		IdleTimeout:     20 * time.Millisecond,
+		LongPollTimeout: 50 * time.Millisecond,
+		MaxDevices:      2,
+	}, s)
+	t.Cleanup(w.Stop)
+	return w
+}
+
+func TestWatcher(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	s := &testService{}
+	w := newTestWatcher(t, s)
+
+	p1, err := w.subscribe("mac:112233445566", "a0")
+	require.NoError(err)
+	p2, err := w.subscribe("mac:112233445566", "a0")
+	require.NoError(err)
+	assert.Same(p1, p2)
+
+	// other credentials get their own poll
+	p3, err := w.subscribe("mac:112233445566", "a1")
+	require.NoError(err)
+	assert.NotSame(p1, p3)
+
+	_, err = w.subscribe("mac:aabbccddeeff", "a0")
+	assert.Equal(ErrTooManyWatches, err)
+
+	require.Eventually(func() bool {
+		data, _, _ := p1.current()
+		return data != nil
+	}, time.Second, time.Millisecond)
+
+	data, etag, changed := p1.current()
+	assert.JSONEq(`{"id": "mac:112233445566", "online": true, "statusCode": 200, "connectedAt": "2026-11-01T02:00:00Z"}`, string(data))
+
+	// unchanged stats aren't reported
+	time.Sleep(20 * time.Millisecond)
+	_, same, _ := p1.current()
+	assert.Equal(etag, same)
+
+	s.set(http.StatusNotFound, "")
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		require.Fail("no change reported")
+	}
+	data, _, _ = p1.current()
+	assert.JSONEq(`{"id": "mac:112233445566", "online": false, "statusCode": 404}`, string(data))
+
+	s.mu.Lock()
+	assert.Len(s.auths, 2)
+	s.mu.Unlock()
+
+	// polls stop once idle
+	w.unsubscribe(p1)
+	w.unsubscribe(p2)
+	w.unsubscribe(p3)
+	require.Eventually(func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return len(w.polls) == 0
+	}, time.Second, time.Millisecond)
+
+	// let a request started before the poll stopped complete
+	time.Sleep(10 * time.Millisecond)
+	s.mu.Lock()
+	requests := s.requests
+	s.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	s.mu.Lock()
+	assert.Equal(requests, s.requests)
+	s.mu.Unlock()
+}
+
+func TestWatchHandler(t *testing.T) {
+	s := &testService{}
+	router := mux.NewRouter()
+	router.Handle("/device/{deviceid}/stat/watch", &watchHandler{watcher: newTestWatcher(t, s)})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	get := func(t *testing.T, header http.Header) *http.Response {
+		r, err := http.NewRequest(http.MethodGet, server.URL+"/device/mac:112233445566/stat/watch", nil)
+		require.NoError(t, err)
+		for k, v := range header {
+			r.Header[k] = v
+		}
+		resp, err := http.DefaultClient.Do(r)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("long poll", func(t *testing.T) {
+		assert := assert.New(t)
+
+		resp := get(t, nil)
+		resp.Body.Close()
+		assert.Equal(http.StatusOK, resp.StatusCode)
+		etag := resp.Header.Get("ETag")
+		assert.NotEmpty(etag)
+
+		resp = get(t, http.Header{"If-None-Match": {etag}})
+		resp.Body.Close()
+		assert.Equal(http.StatusNotModified, resp.StatusCode)
+		assert.Equal(etag, resp.Header.Get("ETag"))
+	})
+
+	t.Run("events", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		s.set(http.StatusOK, testStatBody)
+		resp := get(t, http.Header{"Accept": {mediaTypeEventStream}})
+		defer resp.Body.Close()
+		assert.Equal(http.StatusOK, resp.StatusCode)
+		assert.Equal(mediaTypeEventStream, resp.Header.Get("Content-Type"))
+
+		events := bufio.NewScanner(resp.Body)
+		next := func() string {
+			var lines []string
+			for events.Scan() && events.Text() != "" {
+				lines = append(lines, events.Text())
+			}
+			return strings.Join(lines, "\n")
+		}
+
+		assert.Contains(next(), `data: {"id":"mac:112233445566","online":true`)
+
+		s.set(http.StatusNotFound, "")
+		event := next()
+		assert.Contains(event, "event: presence")
+		require.Contains(event, `data: {"id":"mac:112233445566","online":false,"statusCode":404}`)
+	})
+
+	t.Run("rejected credentials", func(t *testing.T) {
+		s.set(http.StatusUnauthorized, "")
+		r, err := http.NewRequest(http.MethodGet, server.URL+"/device/mac:aabbccddeeff/stat/watch", nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(r)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("invalid device", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, server.URL+"/device/nope/stat/watch", nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(r)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestWatcherStop(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	s := &testService{}
+	w := newTestWatcher(t, s)
+
+	p, err := w.subscribe("mac:112233445566", "a0")
+	require.NoError(err)
+
+	w.Stop()
+	select {
+	case <-p.ctx.Done():
+	case <-time.After(time.Second):
+		require.Fail("poll not stopped")
+	}
+
+	w.mu.Lock()
+	assert.Empty(w.polls)
+	w.mu.Unlock()
+
+	_, err = w.subscribe("mac:112233445566", "a0")
+	assert.Equal(ErrWatchStopped, err)
+}
+
+func TestWatcherRejected(t *testing.T) {
+	for _, code := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		t.Run(http.StatusText(code), func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			s := &testService{}
+			w := newTestWatcher(t, s)
+
+			p, err := w.subscribe("mac:112233445566", "a0")
+			require.NoError(err)
+
+			s.set(code, "")
+			select {
+			case <-p.ctx.Done():
+			case <-time.After(time.Second):
+				require.Fail("poll not stopped")
+			}
+
+			w.mu.Lock()
+			assert.Empty(w.polls)
+			w.mu.Unlock()
+
+			var ce transaction.CodedError
+			require.ErrorAs(p.stopErr(), &ce)
+			assert.Equal(code, ce.StatusCode())
+
+			// later watchers start a new poll
+			p2, err := w.subscribe("mac:112233445566", "a0")
+			require.NoError(err)
+			assert.NotSame(p, p2)
+		})
+	}
+}
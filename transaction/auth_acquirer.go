@@ -13,13 +13,14 @@ import (
 	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/xmidt-org/bascule/basculehttp"
 )
 
 // AuthAcquirer provides a mechanism to acquire authentication tokens for outbound requests.
 // This is used by the translation and stat services to acquire credentials for the XMiDT API.
 type AuthAcquirer interface {
-	Acquire() (string, error)
+	Acquire(context.Context) (string, error)
 }
 
 // BasicAcquirer provides HTTP Basic authentication credentials.
@@ -32,7 +33,7 @@ type BasicAcquirer struct {
 // Acquire returns the HTTP Basic authentication header value.
 // If Username is set, uses basculehttp.BasicAuth to encode credentials as base64.
 // Otherwise returns the pre-formatted Token.
-func (b *BasicAcquirer) Acquire() (string, error) {
+func (b *BasicAcquirer) Acquire(context.Context) (string, error) {
 	if b.Username != "" {
 		return "Basic " + basculehttp.BasicAuth(b.Username, b.Password), nil
 	}
@@ -49,76 +50,310 @@ type RemoteBearerTokenAcquirerOptions struct {
 	AuthURL string        `json:"authURL"`
 	Timeout time.Duration `json:"timeout"`
 	Buffer  time.Duration `json:"buffer"`
+
+	// Fallback is how long past its expiry the last good token is still used
+	// while the auth URL can't be reached.
+	// (Optional) Defaults to no fallback.
+	Fallback time.Duration `json:"fallback"`
 }
 
 // JwtAcquirer obtains a bearer token from remote endpoint.
 type JwtAcquirer struct {
 	Config RemoteBearerTokenAcquirerOptions
-	mu     sync.RWMutex
-	token  string
-	expiry time.Time
+
+	// Client is used to fetch tokens from the auth URL.
+	// (Optional) Defaults to http.DefaultClient.
+	Client *http.Client
+
+	// Metrics records token fetches.
+	// (Optional)
+	Metrics AcquirerMetrics
+
+	tokenCache
 }
 
 // Acquire gets a JWT token from the configured auth URL, using cached token if still valid
-func (j *JwtAcquirer) Acquire() (string, error) {
-	j.mu.RLock()
-	// Check if we have a valid cached token
-	if j.token != "" && time.Now().Before(j.expiry) {
-		defer j.mu.RUnlock()
-		return string(basculehttp.SchemeBearer) + " " + j.token, nil
+func (j *JwtAcquirer) Acquire(ctx context.Context) (string, error) {
+	token, err := j.acquire(ctx, tokenCacheOptions{
+		Timeout:  j.Config.Timeout,
+		Buffer:   j.Config.Buffer,
+		Fallback: j.Config.Fallback,
+		Metrics:  j.Metrics,
+	}, j.fetch)
+	if err != nil {
+		return "", err
 	}
-	j.mu.RUnlock()
 
-	// Token is missing or expired, fetch a new one
-	ctx, cancel := context.WithTimeout(context.Background(), j.Config.Timeout)
-	defer cancel()
+	return string(basculehttp.SchemeBearer) + " " + token, nil
+}
 
+func (j *JwtAcquirer) fetch(ctx context.Context) (tokenResponse, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.Config.AuthURL, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to create token request: %w", err)
+		return tokenResponse{}, fmt.Errorf("failed to create token request: %w", err)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	return fetchToken(j.Client, req)
+}
+
+// tokenResponse is the subset of an OAuth2 style token response tr1d1um uses.
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// fetchToken sends req with client, or http.DefaultClient if client is nil,
+// and decodes the token response.
+func fetchToken(client *http.Client, req *http.Request) (tokenResponse, error) {
+	var tokenResp tokenResponse
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch token from %s: %w", j.Config.AuthURL, err)
+		return tokenResp, fmt.Errorf("failed to fetch token from %s: %w", req.URL, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("token endpoint returned status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var tokenResp struct {
-		AccessToken string `json:"access_token"`
-		TokenType   string `json:"token_type"`
-		ExpiresIn   int    `json:"expires_in"`
+		return tokenResp, fmt.Errorf("token endpoint returned status %d: %s", resp.StatusCode, string(body))
 	}
 
 	// Validate the token response
 	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
-		return "", fmt.Errorf("failed to parse token response: %w", err)
+		return tokenResp, fmt.Errorf("failed to parse token response: %w", err)
 	}
 
 	if tokenResp.AccessToken == "" {
-		return "", errors.New("token response missing access_token field")
+		return tokenResp, errors.New("token response missing access_token field")
+	}
+
+	return tokenResp, nil
+}
+
+// Outcome label values for AcquirerMetrics.
+const (
+	FetchSuccess = "success"
+	FetchFailure = "failure"
+)
+
+// AcquirerMetrics records the token fetches made by an acquirer.
+// Nil fields are skipped.
+type AcquirerMetrics struct {
+	// Fetches counts token fetches by outcome.
+	Fetches *prometheus.CounterVec
+
+	// FetchDuration observes the latency of token fetches by outcome.
+	FetchDuration prometheus.ObserverVec
+}
+
+func (m AcquirerMetrics) observe(err error, d time.Duration) {
+	outcome := FetchSuccess
+	if err != nil {
+		outcome = FetchFailure
+	}
+
+	if m.Fetches != nil {
+		m.Fetches.WithLabelValues(outcome).Inc()
 	}
+	if m.FetchDuration != nil {
+		m.FetchDuration.WithLabelValues(outcome).Observe(d.Seconds())
+	}
+}
+
+type tokenCacheOptions struct {
+	Timeout  time.Duration
+	Buffer   time.Duration
+	Fallback time.Duration
+	Metrics  AcquirerMetrics
+}
+
+// tokenFetch is a token request shared by every caller that needs it.
+type tokenFetch struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+// Backoff bounds of failed token fetches.
+const (
+	minFetchBackoff = time.Second
+	maxFetchBackoff = time.Minute
+)
+
+// tokenCache holds the last fetched token. Once a token is within the buffer
+// of its expiry it is refreshed in the background while callers keep using it,
+// and concurrent fetches are collapsed into a single request. Failed fetches
+// are retried with an exponential backoff.
+type tokenCache struct {
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time
+	expiresAt time.Time
+	inflight  *tokenFetch
+	timer     *time.Timer
+	stopped   bool
+
+	// err is the error of the last fetch, which isn't retried before retryAt.
+	err     error
+	retryAt time.Time
+	backoff time.Duration
+}
 
-	// Cache the valid token with buffer subtracted from expiry
-	j.mu.Lock()
-	defer j.mu.Unlock()
+// acquire returns a valid token, fetching one if needed. Callers only block
+// when there's no unexpired token, and stop waiting when ctx is done. If the
+// fetch fails, the last good token is returned until Fallback past its expiry,
+// without waiting while a fetch is in flight or backing off.
+func (c *tokenCache) acquire(ctx context.Context, o tokenCacheOptions, fetch func(context.Context) (tokenResponse, error)) (string, error) {
+	now := time.Now()
 
-	j.token = tokenResp.AccessToken
-	if tokenResp.ExpiresIn > 0 {
+	c.mu.Lock()
+	token, refreshAt, expiresAt := c.token, c.refreshAt, c.expiresAt
+	if token != "" && now.Before(refreshAt) {
+		c.mu.Unlock()
+		return token, nil
+	}
+
+	usable := token != "" && now.Before(expiresAt.Add(o.Fallback))
+	if now.Before(c.retryAt) {
+		err := c.err
+		c.mu.Unlock()
+		if usable {
+			return token, nil
+		}
+		return "", err
+	}
+
+	busy := c.inflight != nil
+	f := c.startFetch(o, fetch)
+	c.mu.Unlock()
+
+	if token != "" && now.Before(expiresAt) {
+		// still valid, the refresh completes in the background
+		return token, nil
+	}
+	if busy && usable {
+		// the auth URL is slow or down, don't hold callers up on it
+		return token, nil
+	}
+
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+
+	if f.err != nil {
+		if usable {
+			return token, nil
+		}
+		return "", f.err
+	}
+
+	return f.token, nil
+}
+
+// startFetch returns the fetch in progress or starts a new one.
+// c.mu must be held.
+func (c *tokenCache) startFetch(o tokenCacheOptions, fetch func(context.Context) (tokenResponse, error)) *tokenFetch {
+	if c.inflight != nil {
+		return c.inflight
+	}
+
+	f := &tokenFetch{done: make(chan struct{})}
+	c.inflight = f
+
+	go func() {
+		// the fetch isn't tied to any one caller's context since others may be waiting on it
+		ctx, cancel := context.WithTimeout(context.Background(), o.Timeout)
+		defer cancel()
+
+		start := time.Now()
+		resp, err := fetch(ctx)
+		o.Metrics.observe(err, time.Since(start))
+
+		c.mu.Lock()
+		if err == nil {
+			c.store(resp, o, fetch)
+			f.token = resp.AccessToken
+		} else {
+			c.fail(err, o, fetch)
+		}
+		f.err = err
+		c.inflight = nil
+		c.mu.Unlock()
+
+		close(f.done)
+	}()
+
+	return f
+}
+
+// store caches the token from resp and schedules its background refresh
+// Buffer ahead of its expiry. c.mu must be held.
+func (c *tokenCache) store(resp tokenResponse, o tokenCacheOptions, fetch func(context.Context) (tokenResponse, error)) {
+	now := time.Now()
+
+	c.token = resp.AccessToken
+	if resp.ExpiresIn > 0 {
 		// Refresh before expiry by subtracting the buffer duration
-		expiryTime := time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
-		j.expiry = expiryTime.Add(-j.Config.Buffer)
+		c.expiresAt = now.Add(time.Duration(resp.ExpiresIn) * time.Second)
+		c.refreshAt = c.expiresAt.Add(-o.Buffer)
 	} else {
 		// If no expiry info, cache for the buffer duration
-		j.expiry = time.Now().Add(j.Config.Buffer)
+		c.expiresAt = now.Add(o.Buffer)
+		c.refreshAt = c.expiresAt
+	}
+	c.err, c.retryAt, c.backoff = nil, time.Time{}, 0
+
+	c.schedule(c.refreshAt.Sub(now), o, fetch)
+}
+
+// fail records a failed fetch, doubling the backoff, and retries it in the
+// background while the cached token can still be used. c.mu must be held.
+func (c *tokenCache) fail(err error, o tokenCacheOptions, fetch func(context.Context) (tokenResponse, error)) {
+	c.backoff = min(max(2*c.backoff, minFetchBackoff), maxFetchBackoff)
+	c.err = err
+	c.retryAt = time.Now().Add(c.backoff)
+
+	if c.token != "" && c.retryAt.Before(c.expiresAt.Add(o.Fallback)) {
+		c.schedule(c.backoff, o, fetch)
+	}
+}
+
+// schedule starts a background fetch after d, replacing any scheduled one.
+// c.mu must be held.
+func (c *tokenCache) schedule(d time.Duration, o tokenCacheOptions, fetch func(context.Context) (tokenResponse, error)) {
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	if c.stopped || d <= 0 {
+		return
 	}
 
-	return string(basculehttp.SchemeBearer) + " " + j.token, nil
+	c.timer = time.AfterFunc(d, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if !c.stopped {
+			c.startFetch(o, fetch)
+		}
+	})
+}
+
+// Stop cancels any scheduled background refresh. Tokens are still fetched
+// on demand afterwards.
+func (c *tokenCache) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stopped = true
+	if c.timer != nil {
+		c.timer.Stop()
+	}
 }
 
 // PartnerKeys returns the expected keys for partner information in JWT tokens
@@ -4,6 +4,8 @@
 package transaction
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -12,6 +14,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/xmidt-org/bascule/basculehttp"
@@ -48,7 +52,7 @@ func TestBasicAcquirerAcquire(t *testing.T) {
 
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
-			actual, err := tc.acquirer.Acquire()
+			actual, err := tc.acquirer.Acquire(context.Background())
 			if tc.expectError != "" {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tc.expectError)
@@ -156,11 +160,14 @@ func TestJwtAcquirerAcquire(t *testing.T) {
 					Timeout: tc.timeout,
 					Buffer:  tc.buffer,
 				},
-				token:  tc.cachedToken,
-				expiry: tc.cachedExpiry,
+				tokenCache: tokenCache{
+					token:     tc.cachedToken,
+					refreshAt: tc.cachedExpiry,
+					expiresAt: tc.cachedExpiry,
+				},
 			}
 
-			actual, err := acquirer.Acquire()
+			actual, err := acquirer.Acquire(context.Background())
 			if tc.expectErr != "" {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tc.expectErr)
@@ -170,9 +177,10 @@ func TestJwtAcquirerAcquire(t *testing.T) {
 
 			require.NoError(t, err)
 			assert.Equal(t, tc.expected, actual)
+			defer acquirer.Stop()
 
 			if tc.twoAcquires {
-				second, secondErr := acquirer.Acquire()
+				second, secondErr := acquirer.Acquire(context.Background())
 				require.NoError(t, secondErr)
 				assert.Equal(t, tc.expected, second)
 			}
@@ -182,6 +190,264 @@ func TestJwtAcquirerAcquire(t *testing.T) {
 	}
 }
 
+func TestTokenCacheSingleflight(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	fetch := func(context.Context) (tokenResponse, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return tokenResponse{AccessToken: "token-1", ExpiresIn: 120}, nil
+	}
+
+	var c tokenCache
+	defer c.Stop()
+	o := tokenCacheOptions{Timeout: time.Second, Buffer: time.Second}
+
+	const callers = 10
+	results := make(chan string, callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			token, err := c.acquire(context.Background(), o, fetch)
+			assert.NoError(t, err)
+			results <- token
+		}()
+	}
+
+	// let every caller join the fetch before it completes
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.inflight != nil
+	}, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	for i := 0; i < callers; i++ {
+		assert.Equal(t, "token-1", <-results)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestTokenCacheAcquire(t *testing.T) {
+	errFetch := errors.New("auth server down")
+
+	tcs := []struct {
+		name        string
+		token       string
+		refreshIn   time.Duration
+		expiresIn   time.Duration
+		fallback    time.Duration
+		fetching    bool
+		retryIn     time.Duration
+		fetchErr    error
+		expected    string
+		expectErr   error
+		expectFetch bool
+	}{
+		{
+			name:      "fresh token is served from cache",
+			token:     "cached",
+			refreshIn: time.Minute,
+			expiresIn: 2 * time.Minute,
+			expected:  "cached",
+		},
+		{
+			name:        "token in refresh window is served while refreshing",
+			token:       "cached",
+			refreshIn:   -time.Second,
+			expiresIn:   time.Minute,
+			expected:    "cached",
+			expectFetch: true,
+		},
+		{
+			name:        "expired token is replaced",
+			token:       "cached",
+			refreshIn:   -time.Minute,
+			expiresIn:   -time.Second,
+			expected:    "fetched",
+			expectFetch: true,
+		},
+		{
+			name:        "expired token is used within the fallback window",
+			token:       "cached",
+			refreshIn:   -time.Minute,
+			expiresIn:   -time.Second,
+			fallback:    time.Minute,
+			fetchErr:    errFetch,
+			expected:    "cached",
+			expectFetch: true,
+		},
+		{
+			name:        "expired token past the fallback window fails",
+			token:       "cached",
+			refreshIn:   -time.Hour,
+			expiresIn:   -time.Minute,
+			fallback:    time.Second,
+			fetchErr:    errFetch,
+			expectErr:   errFetch,
+			expectFetch: true,
+		},
+		{
+			name:      "expired token is used while a fetch is in flight",
+			token:     "cached",
+			refreshIn: -time.Minute,
+			expiresIn: -time.Second,
+			fallback:  time.Minute,
+			fetching:  true,
+			expected:  "cached",
+		},
+		{
+			name:      "expired token is used while a failed fetch backs off",
+			token:     "cached",
+			refreshIn: -time.Minute,
+			expiresIn: -time.Second,
+			fallback:  time.Minute,
+			retryIn:   time.Minute,
+			fetchErr:  errFetch,
+			expected:  "cached",
+		},
+		{
+			name:      "failed fetch isn't retried before its backoff",
+			retryIn:   time.Minute,
+			fetchErr:  errFetch,
+			expectErr: errFetch,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			fetched := make(chan struct{}, 1)
+			fetch := func(context.Context) (tokenResponse, error) {
+				fetched <- struct{}{}
+				if tc.fetchErr != nil {
+					return tokenResponse{}, tc.fetchErr
+				}
+				return tokenResponse{AccessToken: "fetched", ExpiresIn: 120}, nil
+			}
+
+			now := time.Now()
+			c := tokenCache{
+				token:     tc.token,
+				refreshAt: now.Add(tc.refreshIn),
+				expiresAt: now.Add(tc.expiresIn),
+			}
+			if tc.fetching {
+				c.inflight = &tokenFetch{done: make(chan struct{})}
+			}
+			if tc.retryIn > 0 {
+				c.err, c.retryAt = tc.fetchErr, now.Add(tc.retryIn)
+			}
+			defer c.Stop()
+
+			actual, err := c.acquire(context.Background(), tokenCacheOptions{
+				Timeout:  time.Second,
+				Buffer:   time.Second,
+				Fallback: tc.fallback,
+			}, fetch)
+
+			if tc.expectErr != nil {
+				assert.ErrorIs(t, err, tc.expectErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, actual)
+			}
+
+			if tc.expectFetch {
+				select {
+				case <-fetched:
+				case <-time.After(time.Second):
+					t.Fatal("expected a token fetch")
+				}
+			} else {
+				assert.Empty(t, fetched)
+			}
+		})
+	}
+}
+
+func TestTokenCacheHonorsCallerContext(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	fetch := func(context.Context) (tokenResponse, error) {
+		<-release
+		return tokenResponse{AccessToken: "late"}, nil
+	}
+
+	var c tokenCache
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.acquire(ctx, tokenCacheOptions{Timeout: time.Second}, fetch)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestTokenCacheBackgroundRefresh(t *testing.T) {
+	var calls int32
+	fetch := func(context.Context) (tokenResponse, error) {
+		n := atomic.AddInt32(&calls, 1)
+		return tokenResponse{AccessToken: fmt.Sprintf("token-%d", n), ExpiresIn: 1}, nil
+	}
+
+	var c tokenCache
+	o := tokenCacheOptions{Timeout: time.Second, Buffer: 900 * time.Millisecond}
+
+	token, err := c.acquire(context.Background(), o, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+
+	// the refresh runs ahead of expiry without any caller asking for it
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&calls) >= 2
+	}, 2*time.Second, 10*time.Millisecond)
+
+	c.Stop()
+	stoppedAt := atomic.LoadInt32(&calls)
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, stoppedAt, atomic.LoadInt32(&calls))
+}
+
+func TestTokenCacheRetriesFailedRefresh(t *testing.T) {
+	var calls int32
+	fetch := func(context.Context) (tokenResponse, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return tokenResponse{}, errors.New("auth server down")
+		}
+		return tokenResponse{AccessToken: "refreshed", ExpiresIn: 120}, nil
+	}
+
+	now := time.Now()
+	c := tokenCache{token: "cached", refreshAt: now.Add(-time.Second), expiresAt: now.Add(time.Minute)}
+	defer c.Stop()
+	o := tokenCacheOptions{Timeout: time.Second, Buffer: time.Second}
+
+	token, err := c.acquire(context.Background(), o, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, "cached", token)
+
+	// the failed refresh is retried after its backoff without any caller asking for it
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.token == "refreshed"
+	}, 3*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestAcquirerMetrics(t *testing.T) {
+	fetches := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "fetches"}, []string{"outcome"})
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "duration"}, []string{"outcome"})
+	m := AcquirerMetrics{Fetches: fetches, FetchDuration: duration}
+
+	m.observe(nil, time.Second)
+	m.observe(nil, time.Second)
+	m.observe(errors.New("failed"), time.Second)
+	AcquirerMetrics{}.observe(nil, time.Second)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(fetches.WithLabelValues(FetchSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(fetches.WithLabelValues(FetchFailure)))
+	assert.Equal(t, 2, testutil.CollectAndCount(duration))
+}
+
 func TestPartnerKeys(t *testing.T) {
 	tcs := []struct {
 		name     string
@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cast"
+	"github.com/xmidt-org/bascule"
+)
+
+// APIs whose outbound auth can be configured separately.
+const (
+	APIDevice = "device"
+	APIStat   = "stat"
+)
+
+// Outbound auth modes.
+const (
+	// AuthModePassThrough forwards the caller's Authorization header.
+	AuthModePassThrough = "passthrough"
+
+	// AuthModeService uses tr1d1um's own identity from the AuthAcquirer.
+	AuthModeService = "service"
+
+	// AuthModeExchange mints a downstream token carrying the caller's
+	// principal and partners through the TokenExchanger.
+	AuthModeExchange = "exchange"
+)
+
+var (
+	errNoAcquirer  = errors.New("service auth mode requires an auth acquirer")
+	errNoExchanger = errors.New("exchange auth mode requires a token exchanger")
+	errNoPrincipal = NewCodedError(errors.New("token exchange requires an authenticated caller"), http.StatusUnauthorized)
+	errNoPartners  = NewCodedError(errors.New("token exchange requires a caller token with partners"), http.StatusForbidden)
+)
+
+// AuthPolicy chooses the outbound auth mode for each API and device service.
+// Modes are one of "passthrough", "service" or "exchange".
+type AuthPolicy struct {
+	// Default applies to requests without a more specific mode.
+	// (Optional) Defaults to "service" when an AuthAcquirer is configured and
+	// "passthrough" otherwise.
+	Default string
+
+	// APIs maps an API, "device" or "stat", to its mode.
+	// (Optional)
+	APIs map[string]string
+
+	// Services maps a device service, such as "config", to its mode. It takes
+	// precedence over the "device" entry in APIs.
+	// (Optional)
+	Services map[string]string
+}
+
+// Mode returns the auth mode configured for the given api and service, or
+// the fallback if none is.
+func (p AuthPolicy) Mode(api, service, fallback string) string {
+	if m := p.Services[service]; service != "" && m != "" {
+		return m
+	}
+	if m := p.APIs[api]; m != "" {
+		return m
+	}
+	if p.Default != "" {
+		return p.Default
+	}
+	return fallback
+}
+
+func (p AuthPolicy) modes() map[string]string {
+	modes := map[string]string{"default": p.Default}
+	for k, v := range p.APIs {
+		modes["apis."+k] = v
+	}
+	for k, v := range p.Services {
+		modes["services."+k] = v
+	}
+	return modes
+}
+
+// OutboundAuth decides the Authorization header value of requests sent to
+// the XMiDT API according to an AuthPolicy.
+type OutboundAuth struct {
+	Policy AuthPolicy
+
+	// Acquirer provides the service identity.
+	// (Optional)
+	Acquirer AuthAcquirer
+
+	// Exchanger mints downstream tokens for callers.
+	// (Optional)
+	Exchanger TokenExchanger
+}
+
+// Validate checks that every configured mode is known and that the
+// components it needs are present.
+func (o OutboundAuth) Validate() error {
+	var errs error
+	for key, mode := range o.Policy.modes() {
+		switch mode {
+		case "", AuthModePassThrough:
+		case AuthModeService:
+			if o.Acquirer == nil {
+				errs = errors.Join(errs, fmt.Errorf("%s: %w", key, errNoAcquirer))
+			}
+		case AuthModeExchange:
+			if o.Exchanger == nil {
+				errs = errors.Join(errs, fmt.Errorf("%s: %w", key, errNoExchanger))
+			}
+		default:
+			errs = errors.Join(errs, fmt.Errorf("%s: unknown auth mode '%s'", key, mode))
+		}
+	}
+	return errs
+}
+
+// Mode returns the auth mode of the requests made for the given api and
+// service.
+func (o OutboundAuth) Mode(api, service string) string {
+	fallback := AuthModePassThrough
+	if o.Acquirer != nil {
+		fallback = AuthModeService
+	}
+	return o.Policy.Mode(api, service, fallback)
+}
+
+// Authorization returns the Authorization header value for an outbound
+// request made for the given api and service. callerAuth is the caller's own
+// Authorization header. Exchanged tokens carry the partners of the caller's
+// token only, never the ones of the request headers, which the caller chooses.
+func (o OutboundAuth) Authorization(ctx context.Context, api, service, callerAuth string) (string, error) {
+	switch o.Mode(api, service) {
+	case AuthModeService:
+		if o.Acquirer == nil {
+			return "", errNoAcquirer
+		}
+		return o.Acquirer.Acquire(ctx)
+	case AuthModeExchange:
+		if o.Exchanger == nil {
+			return "", errNoExchanger
+		}
+
+		token, ok := bascule.Get(ctx)
+		if !ok || token.Principal() == "" {
+			return "", errNoPrincipal
+		}
+		partnerIDs, ok := TokenPartnerIDs(token)
+		if !ok {
+			return "", errNoPartners
+		}
+
+		return o.Exchanger.Exchange(ctx, Subject{
+			Principal:  token.Principal(),
+			PartnerIDs: partnerIDs,
+			Token:      credentials(callerAuth),
+		})
+	default:
+		return callerAuth, nil
+	}
+}
+
+// credentials strips the scheme from an Authorization header value.
+func credentials(auth string) string {
+	if _, c, ok := strings.Cut(auth, " "); ok {
+		return c
+	}
+	return auth
+}
+
+// TokenPartnerIDs returns the partner IDs carried in the attributes of a
+// bascule token, if any.
+func TokenPartnerIDs(token bascule.Token) ([]string, bool) {
+	accessor, ok := token.(bascule.AttributesAccessor)
+	if !ok {
+		return nil, false
+	}
+
+	// First try simple top-level partner keys
+	for _, key := range PartnerKeys() {
+		if partnerVal, found := accessor.Get(key); found {
+			partnerIDs, err := cast.ToStringSliceE(partnerVal)
+			if err == nil {
+				return partnerIDs, true
+			}
+		}
+	}
+
+	// Try nested path: allowedResources.allowedPartners
+	partnerIDs, ok := bascule.GetAttribute[[]interface{}](accessor, "allowedResources", "allowedPartners")
+	if ok && len(partnerIDs) > 0 {
+		strIDs, err := cast.ToStringSliceE(partnerIDs)
+		if err == nil {
+			return strIDs, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,239 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package transaction
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/bascule"
+)
+
+type fakeAcquirer struct {
+	auth string
+	err  error
+}
+
+func (f fakeAcquirer) Acquire(context.Context) (string, error) {
+	return f.auth, f.err
+}
+
+type fakeExchanger struct {
+	subject Subject
+	err     error
+}
+
+func (f *fakeExchanger) Exchange(_ context.Context, s Subject) (string, error) {
+	f.subject = s
+	if f.err != nil {
+		return "", f.err
+	}
+	return "Bearer exchanged-" + s.Principal, nil
+}
+
+type attrToken struct {
+	principal string
+	attrs     map[string]interface{}
+}
+
+func (t attrToken) Principal() string { return t.principal }
+
+func (t attrToken) Get(key string) (interface{}, bool) {
+	v, ok := t.attrs[key]
+	return v, ok
+}
+
+func TestAuthPolicyMode(t *testing.T) {
+	policy := AuthPolicy{
+		Default:  AuthModePassThrough,
+		APIs:     map[string]string{APIDevice: AuthModeService},
+		Services: map[string]string{"config": AuthModeExchange},
+	}
+
+	tcs := []struct {
+		name     string
+		policy   AuthPolicy
+		api      string
+		service  string
+		expected string
+	}{
+		{name: "service overrides api", policy: policy, api: APIDevice, service: "config", expected: AuthModeExchange},
+		{name: "api mode", policy: policy, api: APIDevice, service: "iot", expected: AuthModeService},
+		{name: "default mode", policy: policy, api: APIStat, expected: AuthModePassThrough},
+		{name: "fallback", api: APIStat, expected: AuthModeService},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.policy.Mode(tc.api, tc.service, AuthModeService))
+		})
+	}
+}
+
+func TestOutboundAuthValidate(t *testing.T) {
+	tcs := []struct {
+		name      string
+		auth      OutboundAuth
+		expectErr []string
+	}{
+		{
+			name: "empty policy",
+		},
+		{
+			name: "all modes available",
+			auth: OutboundAuth{
+				Policy: AuthPolicy{
+					Default:  AuthModeService,
+					APIs:     map[string]string{APIStat: AuthModePassThrough},
+					Services: map[string]string{"config": AuthModeExchange},
+				},
+				Acquirer:  fakeAcquirer{},
+				Exchanger: &fakeExchanger{},
+			},
+		},
+		{
+			name: "missing components and unknown mode",
+			auth: OutboundAuth{
+				Policy: AuthPolicy{
+					Default:  AuthModeService,
+					APIs:     map[string]string{APIStat: "magic"},
+					Services: map[string]string{"config": AuthModeExchange},
+				},
+			},
+			expectErr: []string{
+				"default: service auth mode requires an auth acquirer",
+				"apis.stat: unknown auth mode 'magic'",
+				"services.config: exchange auth mode requires a token exchanger",
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.auth.Validate()
+			if len(tc.expectErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			for _, e := range tc.expectErr {
+				assert.Contains(t, err.Error(), e)
+			}
+		})
+	}
+}
+
+func TestOutboundAuthAuthorization(t *testing.T) {
+	token := attrToken{
+		principal: "user-1",
+		attrs:     map[string]interface{}{"partner-id": []string{"comcast"}},
+	}
+	tokenCtx := bascule.WithToken(context.Background(), token)
+	acquirerErr := errors.New("acquirer failure")
+
+	tcs := []struct {
+		name           string
+		auth           OutboundAuth
+		ctx            context.Context
+		service        string
+		expected       string
+		expectedSubj   Subject
+		expectErr      error
+		expectedStatus int
+	}{
+		{
+			name:     "passthrough without acquirer",
+			ctx:      context.Background(),
+			expected: "Bearer caller",
+		},
+		{
+			name:     "service when acquirer is set",
+			auth:     OutboundAuth{Acquirer: fakeAcquirer{auth: "Bearer service"}},
+			ctx:      context.Background(),
+			expected: "Bearer service",
+		},
+		{
+			name:      "service acquirer error",
+			auth:      OutboundAuth{Acquirer: fakeAcquirer{err: acquirerErr}},
+			ctx:       context.Background(),
+			expectErr: acquirerErr,
+		},
+		{
+			name: "passthrough configured with acquirer",
+			auth: OutboundAuth{
+				Policy:   AuthPolicy{Services: map[string]string{"config": AuthModePassThrough}},
+				Acquirer: fakeAcquirer{auth: "Bearer service"},
+			},
+			ctx:      context.Background(),
+			service:  "config",
+			expected: "Bearer caller",
+		},
+		{
+			name: "exchange with token partners",
+			auth: OutboundAuth{
+				Policy:    AuthPolicy{Default: AuthModeExchange},
+				Exchanger: &fakeExchanger{},
+			},
+			ctx:      tokenCtx,
+			expected: "Bearer exchanged-user-1",
+			expectedSubj: Subject{
+				Principal:  "user-1",
+				PartnerIDs: []string{"comcast"},
+				Token:      "caller",
+			},
+		},
+		{
+			name: "exchange without token partners",
+			auth: OutboundAuth{
+				Policy:    AuthPolicy{Default: AuthModeExchange},
+				Exchanger: &fakeExchanger{},
+			},
+			ctx:            bascule.WithToken(context.Background(), attrToken{principal: "user-1"}),
+			expectErr:      errNoPartners,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "exchange without caller",
+			auth: OutboundAuth{
+				Policy:    AuthPolicy{Default: AuthModeExchange},
+				Exchanger: &fakeExchanger{},
+			},
+			ctx:            context.Background(),
+			expectErr:      errNoPrincipal,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:      "exchange without exchanger",
+			auth:      OutboundAuth{Policy: AuthPolicy{Default: AuthModeExchange}},
+			ctx:       tokenCtx,
+			expectErr: errNoExchanger,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := tc.auth.Authorization(tc.ctx, APIDevice, tc.service, "Bearer caller")
+			if tc.expectErr != nil {
+				assert.ErrorIs(t, err, tc.expectErr)
+				if tc.expectedStatus != 0 {
+					var ce CodedError
+					require.ErrorAs(t, err, &ce)
+					assert.Equal(t, tc.expectedStatus, ce.StatusCode())
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+			if e, ok := tc.auth.Exchanger.(*fakeExchanger); ok {
+				assert.Equal(t, tc.expectedSubj, e.subject)
+			}
+		})
+	}
+}
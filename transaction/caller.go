@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package transaction
+
+import (
+	"context"
+	"slices"
+
+	"github.com/xmidt-org/bascule"
+)
+
+// UnknownPrincipal is the principal of tokens that don't name their subject.
+// Callers with that principal don't own what they create, which is only shared
+// through its partners.
+const UnknownPrincipal = "unknown"
+
+// Caller is who makes a request: the principal and the partners of their
+// token. Partners only come from the token, the partner ID headers being
+// chosen by the caller.
+type Caller struct {
+	Principal  string
+	PartnerIDs []string
+}
+
+// GetCaller returns the caller of a request, empty for unauthenticated ones.
+func GetCaller(ctx context.Context) Caller {
+	token, ok := bascule.Get(ctx)
+	if !ok {
+		return Caller{}
+	}
+	partnerIDs, _ := TokenPartnerIDs(token)
+	return Caller{Principal: token.Principal(), PartnerIDs: partnerIDs}
+}
+
+// Allowed reports whether the caller may access a resource created by owner
+// for partnerIDs: their own resources, and the ones all of whose partners are
+// theirs.
+func (c Caller) Allowed(owner string, partnerIDs []string) bool {
+	if owner == c.Principal && owner != UnknownPrincipal {
+		return true
+	}
+	if len(partnerIDs) == 0 {
+		return false
+	}
+	for _, partnerID := range partnerIDs {
+		if !slices.Contains(c.PartnerIDs, partnerID) {
+			return false
+		}
+	}
+	return true
+}
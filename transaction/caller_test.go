@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package transaction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/bascule"
+)
+
+func TestGetCaller(t *testing.T) {
+	assert.Equal(t, Caller{}, GetCaller(context.Background()))
+
+	ctx := bascule.WithToken(context.Background(), attrToken{
+		principal: "user-1",
+		attrs:     map[string]interface{}{"partner-id": []string{"comcast"}},
+	})
+	assert.Equal(t, Caller{Principal: "user-1", PartnerIDs: []string{"comcast"}}, GetCaller(ctx))
+}
+
+func TestCallerAllowed(t *testing.T) {
+	caller := Caller{Principal: "user-1", PartnerIDs: []string{"comcast", "sky"}}
+
+	tcs := []struct {
+		name       string
+		owner      string
+		partnerIDs []string
+		expected   bool
+	}{
+		{name: "owner", owner: "user-1", partnerIDs: []string{"other"}, expected: true},
+		{name: "partners", owner: "user-2", partnerIDs: []string{"comcast"}, expected: true},
+		{name: "some partners", owner: "user-2", partnerIDs: []string{"comcast", "other"}},
+		{name: "no partners", owner: "user-2"},
+	}
+
+	unknown := Caller{Principal: UnknownPrincipal, PartnerIDs: []string{"comcast"}}
+	assert.False(t, unknown.Allowed(UnknownPrincipal, nil))
+	assert.True(t, unknown.Allowed(UnknownPrincipal, []string{"comcast"}))
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, caller.Allowed(tc.owner, tc.partnerIDs))
+		})
+	}
+}
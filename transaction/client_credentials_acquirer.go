@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/xmidt-org/bascule/basculehttp"
+)
+
+// Supported ways of presenting the client credentials to the token endpoint.
+const (
+	// ClientAuthBasic sends the client ID and secret in an HTTP Basic Authorization header.
+	ClientAuthBasic = "basic"
+
+	// ClientAuthForm sends the client ID and secret as form parameters in the request body.
+	ClientAuthForm = "form"
+)
+
+// ClientCredentialsAcquirerOptions configures an OAuth2 client credentials
+// token request (RFC 6749, section 4.4).
+type ClientCredentialsAcquirerOptions struct {
+	// TokenURL is the OAuth2 token endpoint.
+	TokenURL string `json:"tokenURL"`
+
+	// ClientID and ClientSecret identify tr1d1um to the identity provider.
+	ClientID     string `json:"clientID"`
+	ClientSecret string `json:"clientSecret"`
+
+	// Scopes are requested for the token.
+	// (Optional)
+	Scopes []string `json:"scopes"`
+
+	// Audience is the intended audience of the token.
+	// (Optional)
+	Audience string `json:"audience"`
+
+	// AuthStyle is either "basic" or "form".
+	// (Optional) Defaults to "basic".
+	AuthStyle string `json:"authStyle"`
+
+	// Timeout is how long the token request may take.
+	Timeout time.Duration `json:"timeout"`
+
+	// Buffer is the length of time before a token expires to get a new token.
+	Buffer time.Duration `json:"buffer"`
+
+	// Fallback is how long past its expiry the last good token is still used
+	// while the token URL can't be reached.
+	// (Optional) Defaults to no fallback.
+	Fallback time.Duration `json:"fallback"`
+}
+
+// ClientCredentialsAcquirer obtains a bearer token using the OAuth2 client
+// credentials grant.
+type ClientCredentialsAcquirer struct {
+	Config ClientCredentialsAcquirerOptions
+
+	// Client is used to fetch tokens from the token URL.
+	// (Optional) Defaults to http.DefaultClient.
+	Client *http.Client
+
+	// Metrics records token fetches.
+	// (Optional)
+	Metrics AcquirerMetrics
+
+	tokenCache
+}
+
+// Acquire gets a token from the configured token URL, using cached token if still valid
+func (c *ClientCredentialsAcquirer) Acquire(ctx context.Context) (string, error) {
+	token, err := c.acquire(ctx, tokenCacheOptions{
+		Timeout:  c.Config.Timeout,
+		Buffer:   c.Config.Buffer,
+		Fallback: c.Config.Fallback,
+		Metrics:  c.Metrics,
+	}, c.fetch)
+	if err != nil {
+		return "", err
+	}
+
+	return string(basculehttp.SchemeBearer) + " " + token, nil
+}
+
+func (c *ClientCredentialsAcquirer) fetch(ctx context.Context) (tokenResponse, error) {
+	req, err := c.newRequest(ctx)
+	if err != nil {
+		return tokenResponse{}, err
+	}
+
+	return fetchToken(c.Client, req)
+}
+
+func (c *ClientCredentialsAcquirer) newRequest(ctx context.Context) (*http.Request, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(c.Config.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.Config.Scopes, " "))
+	}
+	if c.Config.Audience != "" {
+		form.Set("audience", c.Config.Audience)
+	}
+
+	authStyle := c.Config.AuthStyle
+	switch authStyle {
+	case "", ClientAuthBasic:
+		authStyle = ClientAuthBasic
+	case ClientAuthForm:
+		form.Set("client_id", c.Config.ClientID)
+		form.Set("client_secret", c.Config.ClientSecret)
+	default:
+		return nil, fmt.Errorf("unsupported client auth style '%s'", authStyle)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if authStyle == ClientAuthBasic {
+		// RFC 6749, section 2.3.1 requires the credentials to be form encoded first.
+		req.SetBasicAuth(url.QueryEscape(c.Config.ClientID), url.QueryEscape(c.Config.ClientSecret))
+	}
+
+	return req, nil
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCredentialsAcquirerAcquire(t *testing.T) {
+	tcs := []struct {
+		name          string
+		config        ClientCredentialsAcquirerOptions
+		serverStatus  int
+		serverBody    string
+		expectForm    url.Values
+		expectBasic   bool
+		expected      string
+		expectErr     string
+		expectedCalls int32
+	}{
+		{
+			name: "basic client auth with scopes and audience",
+			config: ClientCredentialsAcquirerOptions{
+				ClientID:     "tr1d1um",
+				ClientSecret: "s3cr3t&",
+				Scopes:       []string{"device:read", "device:write"},
+				Audience:     "xmidt",
+			},
+			serverStatus: http.StatusOK,
+			serverBody:   `{"access_token":"token-1","token_type":"Bearer","expires_in":120}`,
+			expectForm: url.Values{
+				"grant_type": {"client_credentials"},
+				"scope":      {"device:read device:write"},
+				"audience":   {"xmidt"},
+			},
+			expectBasic:   true,
+			expected:      "Bearer token-1",
+			expectedCalls: 1,
+		},
+		{
+			name: "form client auth",
+			config: ClientCredentialsAcquirerOptions{
+				ClientID:     "tr1d1um",
+				ClientSecret: "s3cr3t",
+				AuthStyle:    ClientAuthForm,
+			},
+			serverStatus: http.StatusOK,
+			serverBody:   `{"access_token":"token-2","expires_in":120}`,
+			expectForm: url.Values{
+				"grant_type":    {"client_credentials"},
+				"client_id":     {"tr1d1um"},
+				"client_secret": {"s3cr3t"},
+			},
+			expected:      "Bearer token-2",
+			expectedCalls: 1,
+		},
+		{
+			name: "unsupported auth style returns error",
+			config: ClientCredentialsAcquirerOptions{
+				ClientID:  "tr1d1um",
+				AuthStyle: "jwt",
+			},
+			expectErr:     "unsupported client auth style",
+			expectedCalls: 0,
+		},
+		{
+			name: "non-200 from token endpoint returns error",
+			config: ClientCredentialsAcquirerOptions{
+				ClientID: "tr1d1um",
+			},
+			serverStatus:  http.StatusUnauthorized,
+			expectBasic:   true,
+			serverBody:    `{"error":"invalid_client"}`,
+			expectErr:     "token endpoint returned status 401",
+			expectedCalls: 1,
+		},
+		{
+			name: "missing access token returns error",
+			config: ClientCredentialsAcquirerOptions{
+				ClientID: "tr1d1um",
+			},
+			serverStatus:  http.StatusOK,
+			serverBody:    `{"token_type":"Bearer"}`,
+			expectBasic:   true,
+			expectErr:     "missing access_token",
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+				assert.NoError(t, r.ParseForm())
+				if tc.expectForm != nil {
+					assert.Equal(t, tc.expectForm, r.PostForm)
+				}
+
+				id, secret, ok := r.BasicAuth()
+				assert.Equal(t, tc.expectBasic, ok)
+				if tc.expectBasic {
+					assert.Equal(t, url.QueryEscape(tc.config.ClientID), id)
+					assert.Equal(t, url.QueryEscape(tc.config.ClientSecret), secret)
+				}
+
+				w.WriteHeader(tc.serverStatus)
+				_, _ = fmt.Fprint(w, tc.serverBody)
+			}))
+			defer srv.Close()
+
+			config := tc.config
+			config.TokenURL = srv.URL
+			config.Timeout = time.Second
+			config.Buffer = time.Second
+			acquirer := &ClientCredentialsAcquirer{Config: config}
+
+			actual, err := acquirer.Acquire(context.Background())
+			if tc.expectErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectErr)
+				assert.Equal(t, tc.expectedCalls, atomic.LoadInt32(&calls))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+
+			// the second call is served from the cache
+			second, err := acquirer.Acquire(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, second)
+			assert.Equal(t, tc.expectedCalls, atomic.LoadInt32(&calls))
+		})
+	}
+}
@@ -9,4 +9,5 @@ type contextKey int
 const (
 	ContextKeyRequestArrivalTime contextKey = iota
 	ContextKeyRequestTID
+	ContextKeyRequestTimeout
 )
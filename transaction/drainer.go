@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package transaction
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrDraining is returned for transactions started after draining began.
+var ErrDraining = errors.New("tr1d1um is shutting down")
+
+// Drainer tracks in-flight transactions so that shutdown can wait for them
+// to finish, and abort the ones that take too long.
+type Drainer struct {
+	mu       sync.Mutex
+	draining bool
+	inFlight int
+	idle     chan struct{}
+
+	aborted atomic.Int64
+
+	abortCtx context.Context
+	abort    context.CancelFunc
+}
+
+// NewDrainer returns a Drainer that accepts transactions until Drain is called.
+func NewDrainer() *Drainer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Drainer{
+		abortCtx: ctx,
+		abort:    cancel,
+	}
+}
+
+// Draining reports whether Drain has been called.
+func (d *Drainer) Draining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.draining
+}
+
+func (d *Drainer) begin() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return false
+	}
+	d.inFlight++
+	return true
+}
+
+func (d *Drainer) end() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.inFlight--
+	if d.inFlight == 0 && d.idle != nil {
+		close(d.idle)
+		d.idle = nil
+	}
+}
+
+// Track returns a T whose transactions are tracked by d. Once draining began,
+// new transactions fail with ErrDraining and a 503.
+func (d *Drainer) Track(t T) T {
+	return transactorFunc(func(req *http.Request) (*XmidtResponse, error) {
+		if !d.begin() {
+			return nil, NewCodedError(ErrDraining, http.StatusServiceUnavailable)
+		}
+		defer d.end()
+
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		stop := context.AfterFunc(d.abortCtx, cancel)
+		defer stop()
+
+		result, err := t.Transact(req.WithContext(ctx))
+		if err != nil && d.abortCtx.Err() != nil {
+			d.aborted.Add(1)
+		}
+		return result, err
+	})
+}
+
+// Drain stops new transactions and waits for the in-flight ones to finish.
+// Those still running when ctx is done are aborted. It returns the number
+// of aborted transactions.
+func (d *Drainer) Drain(ctx context.Context) int {
+	d.mu.Lock()
+	d.draining = true
+	if d.inFlight == 0 {
+		d.mu.Unlock()
+		return int(d.aborted.Load())
+	}
+	if d.idle == nil {
+		d.idle = make(chan struct{})
+	}
+	idle := d.idle
+	d.mu.Unlock()
+
+	select {
+	case <-idle:
+	case <-ctx.Done():
+		d.abort()
+		<-idle
+	}
+
+	return int(d.aborted.Load())
+}
+
+type transactorFunc func(*http.Request) (*XmidtResponse, error)
+
+func (f transactorFunc) Transact(req *http.Request) (*XmidtResponse, error) {
+	return f(req)
+}
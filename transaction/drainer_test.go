@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package transaction
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingTransactor returns a T whose transactions wait for release or for
+// their context to be done.
+func blockingTransactor(started chan<- struct{}, release <-chan struct{}) T {
+	return New(&Options{
+		RequestTimeout: time.Minute,
+		Do: func(r *http.Request) (*http.Response, error) {
+			started <- struct{}{}
+			select {
+			case <-release:
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString("ok"))}, nil
+			case <-r.Context().Done():
+				return nil, r.Context().Err()
+			}
+		},
+	})
+}
+
+func TestDrainerFinishesInFlight(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	d := NewDrainer()
+	transactor := d.Track(blockingTransactor(started, release))
+
+	type result struct {
+		resp *XmidtResponse
+		err  error
+	}
+	results := make(chan result)
+	go func() {
+		resp, err := transactor.Transact(httptest.NewRequest(http.MethodGet, "localhost:6003/test", nil))
+		results <- result{resp, err}
+	}()
+	<-started
+
+	drained := make(chan int)
+	go func() {
+		drained <- d.Drain(context.Background())
+	}()
+
+	assert.Eventually(d.Draining, time.Second, time.Millisecond)
+
+	// new transactions are rejected while draining
+	_, err := transactor.Transact(httptest.NewRequest(http.MethodGet, "localhost:6003/test", nil))
+	require.EqualError(err, ErrDraining.Error())
+	var coded CodedError
+	require.ErrorAs(err, &coded)
+	assert.Equal(http.StatusServiceUnavailable, coded.StatusCode())
+
+	// the in-flight transaction is waited for
+	close(release)
+	r := <-results
+	require.NoError(r.err)
+	assert.Equal(http.StatusOK, r.resp.Code)
+	assert.Equal(0, <-drained)
+}
+
+func TestDrainerAbortsInFlight(t *testing.T) {
+	assert := assert.New(t)
+
+	started := make(chan struct{})
+	d := NewDrainer()
+	transactor := d.Track(blockingTransactor(started, nil))
+
+	errs := make(chan error)
+	go func() {
+		_, err := transactor.Transact(httptest.NewRequest(http.MethodGet, "localhost:6003/test", nil))
+		errs <- err
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(1, d.Drain(ctx))
+	assert.ErrorContains(<-errs, context.Canceled.Error())
+}
+
+func TestDrainerIdle(t *testing.T) {
+	d := NewDrainer()
+	assert.False(t, d.Draining())
+	assert.Equal(t, 0, d.Drain(context.Background()))
+	assert.True(t, d.Draining())
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package transaction
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+)
+
+// HeaderRequestTimeout lets clients choose how long tr1d1um waits for XMiDT.
+// Its value is a duration such as "10s" or a number of seconds.
+const HeaderRequestTimeout = "X-Webpa-Timeout"
+
+// ErrInvalidRequestTimeout is returned for an unparsable HeaderRequestTimeout.
+var ErrInvalidRequestTimeout = errors.New("invalid " + HeaderRequestTimeout + " header, expected a positive duration such as '10s'")
+
+// TimeoutLimits bound the request timeouts clients may ask for.
+// A zero value means no bound.
+type TimeoutLimits struct {
+	Min time.Duration `json:"min"`
+	Max time.Duration `json:"max"`
+}
+
+// TimeoutPolicy bounds the request timeouts clients may ask for per service.
+type TimeoutPolicy struct {
+	// Default applies to services without their own limits.
+	// (Optional)
+	Default TimeoutLimits `json:"default"`
+
+	// Services maps a device service, such as "config", or "stat" to its limits.
+	// (Optional)
+	Services map[string]TimeoutLimits `json:"services"`
+}
+
+// Clamp returns the requested timeout bounded by the limits of the service.
+func (p TimeoutPolicy) Clamp(service string, requested time.Duration) time.Duration {
+	l, ok := p.Services[service]
+	if !ok {
+		l = p.Default
+	}
+
+	if l.Min > 0 && requested < l.Min {
+		return l.Min
+	}
+	if l.Max > 0 && requested > l.Max {
+		return l.Max
+	}
+	return requested
+}
+
+// ParseRequestTimeout returns the timeout requested by the client, if any.
+func ParseRequestTimeout(r *http.Request) (time.Duration, bool, error) {
+	v := r.Header.Get(HeaderRequestTimeout)
+	if v == "" {
+		return 0, false, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		var secs int64
+		if secs, err = strconv.ParseInt(v, 10, 64); err == nil {
+			d = time.Duration(secs) * time.Second
+		}
+	}
+	if err != nil || d <= 0 {
+		return 0, false, NewBadRequestError(ErrInvalidRequestTimeout)
+	}
+
+	return d, true, nil
+}
+
+// CaptureRequestTimeout returns a kithttp.RequestFunc that puts the timeout
+// requested by the client, clamped by the policy, in the request context.
+// service returns the service the request is for. Invalid timeouts are left
+// for DecodeRequestTimeout to reject.
+func CaptureRequestTimeout(policy TimeoutPolicy, service func(*http.Request) string) kithttp.RequestFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		d, ok, err := ParseRequestTimeout(r)
+		if err != nil || !ok {
+			return ctx
+		}
+		return WithRequestTimeout(ctx, policy.Clamp(service(r), d))
+	}
+}
+
+// DecodeRequestTimeout rejects requests with an invalid HeaderRequestTimeout
+// before decoding them with decoder.
+func DecodeRequestTimeout(decoder kithttp.DecodeRequestFunc) kithttp.DecodeRequestFunc {
+	return func(ctx context.Context, r *http.Request) (interface{}, error) {
+		if _, _, err := ParseRequestTimeout(r); err != nil {
+			return nil, err
+		}
+		return decoder(ctx, r)
+	}
+}
+
+// WithRequestTimeout returns a context carrying the timeout of the request
+// to XMiDT.
+func WithRequestTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, ContextKeyRequestTimeout, d)
+}
+
+// GetRequestTimeout returns the timeout of the request to XMiDT, if one was
+// chosen for this request.
+func GetRequestTimeout(ctx context.Context) (time.Duration, bool) {
+	d, ok := ctx.Value(ContextKeyRequestTimeout).(time.Duration)
+	return d, ok
+}
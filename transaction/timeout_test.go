@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package transaction
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutPolicyClamp(t *testing.T) {
+	policy := TimeoutPolicy{
+		Default: TimeoutLimits{Min: time.Second, Max: 30 * time.Second},
+		Services: map[string]TimeoutLimits{
+			"stat":   {Max: 5 * time.Second},
+			"config": {},
+		},
+	}
+
+	tests := []struct {
+		description string
+		service     string
+		requested   time.Duration
+		expected    time.Duration
+	}{
+		{"within default", "other", 10 * time.Second, 10 * time.Second},
+		{"below default min", "other", time.Millisecond, time.Second},
+		{"above default max", "other", time.Minute, 30 * time.Second},
+		{"above service max", "stat", 10 * time.Second, 5 * time.Second},
+		{"no service min", "stat", time.Millisecond, time.Millisecond},
+		{"unbounded service", "config", time.Hour, time.Hour},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expected, policy.Clamp(tc.service, tc.requested))
+		})
+	}
+}
+
+func TestParseRequestTimeout(t *testing.T) {
+	tests := []struct {
+		description string
+		value       string
+		expected    time.Duration
+		expectedOK  bool
+		expectedErr bool
+	}{
+		{description: "absent"},
+		{description: "duration", value: "1500ms", expected: 1500 * time.Millisecond, expectedOK: true},
+		{description: "seconds", value: "12", expected: 12 * time.Second, expectedOK: true},
+		{description: "zero", value: "0", expectedErr: true},
+		{description: "negative", value: "-5s", expectedErr: true},
+		{description: "garbage", value: "soon", expectedErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			r := httptest.NewRequest(http.MethodGet, "http://localhost/test", nil)
+			if tc.value != "" {
+				r.Header.Set(HeaderRequestTimeout, tc.value)
+			}
+
+			d, ok, err := ParseRequestTimeout(r)
+			if tc.expectedErr {
+				assert.EqualError(err, ErrInvalidRequestTimeout.Error())
+				var coded CodedError
+				if assert.ErrorAs(err, &coded) {
+					assert.Equal(http.StatusBadRequest, coded.StatusCode())
+				}
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(tc.expectedOK, ok)
+			assert.Equal(tc.expected, d)
+		})
+	}
+}
+
+func TestCaptureRequestTimeout(t *testing.T) {
+	capture := CaptureRequestTimeout(
+		TimeoutPolicy{Default: TimeoutLimits{Max: 20 * time.Second}},
+		func(*http.Request) string { return "config" },
+	)
+
+	t.Run("clamped", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost/test", nil)
+		r.Header.Set(HeaderRequestTimeout, "1m")
+
+		d, ok := GetRequestTimeout(capture(context.Background(), r))
+		assert.True(t, ok)
+		assert.Equal(t, 20*time.Second, d)
+	})
+
+	t.Run("absent", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost/test", nil)
+
+		_, ok := GetRequestTimeout(capture(context.Background(), r))
+		assert.False(t, ok)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost/test", nil)
+		r.Header.Set(HeaderRequestTimeout, "soon")
+
+		_, ok := GetRequestTimeout(capture(context.Background(), r))
+		assert.False(t, ok)
+	})
+}
+
+func TestDecodeRequestTimeout(t *testing.T) {
+	assert := assert.New(t)
+	decode := DecodeRequestTimeout(func(context.Context, *http.Request) (interface{}, error) {
+		return "decoded", nil
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "http://localhost/test", nil)
+	r.Header.Set(HeaderRequestTimeout, "soon")
+	_, err := decode(context.Background(), r)
+	assert.EqualError(err, ErrInvalidRequestTimeout.Error())
+
+	r.Header.Set(HeaderRequestTimeout, "5s")
+	v, err := decode(context.Background(), r)
+	assert.NoError(err)
+	assert.Equal("decoded", v)
+}
+
+func TestTransactRequestTimeout(t *testing.T) {
+	tests := []struct {
+		description    string
+		requestTimeout time.Duration
+		requested      time.Duration
+	}{
+		{"shortens", time.Minute, time.Second},
+		{"never extends", time.Second, time.Minute},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			require := require.New(t)
+			var remaining time.Duration
+			transactor := New(&Options{
+				RequestTimeout: tc.requestTimeout,
+				Do: func(r *http.Request) (*http.Response, error) {
+					deadline, ok := r.Context().Deadline()
+					require.True(ok)
+					remaining = time.Until(deadline)
+					return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString("ok"))}, nil
+				},
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "http://localhost/test", nil)
+			r = r.WithContext(WithRequestTimeout(r.Context(), tc.requested))
+			_, err := transactor.Transact(r)
+			require.NoError(err)
+
+			shortest := min(tc.requestTimeout, tc.requested)
+			assert.LessOrEqual(t, remaining, shortest)
+			assert.Greater(t, remaining, shortest-time.Second/2)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xmidt-org/bascule/basculehttp"
+)
+
+const (
+	grantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	tokenTypeAccessToken   = "urn:ietf:params:oauth:token-type:access_token"
+
+	// maxExchangedTokens bounds the number of cached downstream tokens.
+	maxExchangedTokens = 1024
+)
+
+// Subject identifies the caller a downstream token is minted for.
+type Subject struct {
+	// Principal is the caller's authenticated identity.
+	Principal string
+
+	// PartnerIDs are the partners the caller may act for.
+	PartnerIDs []string
+
+	// Token is the caller's own credential, without the auth scheme.
+	Token string
+}
+
+// TokenExchanger mints tokens for the XMiDT API on behalf of callers.
+type TokenExchanger interface {
+	// Exchange returns the Authorization header value for the subject.
+	Exchange(context.Context, Subject) (string, error)
+}
+
+// TokenExchangeOptions configures a RemoteTokenExchanger.
+type TokenExchangeOptions struct {
+	// URL is the token exchange endpoint.
+	URL string `json:"url"`
+
+	// Audience is the intended audience of the downstream token.
+	// (Optional)
+	Audience string `json:"audience"`
+
+	// Timeout is how long the exchange request may take.
+	Timeout time.Duration `json:"timeout"`
+
+	// Buffer is the length of time before a downstream token expires to
+	// exchange for a new one.
+	Buffer time.Duration `json:"buffer"`
+}
+
+// RemoteTokenExchanger exchanges caller credentials for downstream tokens
+// using an OAuth2 token exchange (RFC 8693) request. Besides the standard
+// parameters, the caller's principal and comma separated partner IDs are sent
+// as "principal" and "partner_ids" so the issuer can put them in the token.
+type RemoteTokenExchanger struct {
+	Config TokenExchangeOptions
+
+	// Client is used to call the exchange URL.
+	// (Optional) Defaults to http.DefaultClient.
+	Client *http.Client
+
+	// Acquirer authenticates tr1d1um to the exchange endpoint.
+	// (Optional)
+	Acquirer AuthAcquirer
+
+	mu     sync.Mutex
+	tokens map[string]exchangedToken
+}
+
+type exchangedToken struct {
+	token     string
+	refreshAt time.Time
+}
+
+// Exchange returns a downstream bearer token for the subject, using a cached
+// one if still valid.
+func (e *RemoteTokenExchanger) Exchange(ctx context.Context, s Subject) (string, error) {
+	key := s.Principal + "\x00" + strings.Join(s.PartnerIDs, ",")
+
+	e.mu.Lock()
+	cached, ok := e.tokens[key]
+	e.mu.Unlock()
+	if ok && time.Now().Before(cached.refreshAt) {
+		return string(basculehttp.SchemeBearer) + " " + cached.token, nil
+	}
+
+	if e.Config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.Config.Timeout)
+		defer cancel()
+	}
+
+	req, err := e.newRequest(ctx, s)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := fetchToken(e.Client, req)
+	if err != nil {
+		return "", err
+	}
+
+	expiry := time.Now().Add(e.Config.Buffer)
+	if resp.ExpiresIn > 0 {
+		expiry = time.Now().Add(time.Duration(resp.ExpiresIn)*time.Second - e.Config.Buffer)
+	}
+	e.store(key, exchangedToken{token: resp.AccessToken, refreshAt: expiry})
+
+	return string(basculehttp.SchemeBearer) + " " + resp.AccessToken, nil
+}
+
+func (e *RemoteTokenExchanger) newRequest(ctx context.Context, s Subject) (*http.Request, error) {
+	form := url.Values{}
+	form.Set("grant_type", grantTypeTokenExchange)
+	form.Set("requested_token_type", tokenTypeAccessToken)
+	if s.Token != "" {
+		form.Set("subject_token", s.Token)
+		form.Set("subject_token_type", tokenTypeAccessToken)
+	}
+	if e.Config.Audience != "" {
+		form.Set("audience", e.Config.Audience)
+	}
+	form.Set("principal", s.Principal)
+	if len(s.PartnerIDs) > 0 {
+		form.Set("partner_ids", strings.Join(s.PartnerIDs, ","))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.Config.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token exchange request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	if e.Acquirer != nil {
+		auth, err := e.Acquirer.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", auth)
+	}
+
+	return req, nil
+}
+
+// store caches t under key, dropping expired tokens once the cache is full.
+func (e *RemoteTokenExchanger) store(key string, t exchangedToken) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.tokens == nil {
+		e.tokens = make(map[string]exchangedToken)
+	}
+
+	if len(e.tokens) >= maxExchangedTokens {
+		now := time.Now()
+		for k, v := range e.tokens {
+			if !now.Before(v.refreshAt) {
+				delete(e.tokens, k)
+			}
+		}
+		if len(e.tokens) >= maxExchangedTokens {
+			e.tokens = make(map[string]exchangedToken)
+		}
+	}
+
+	e.tokens[key] = t
+}
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteTokenExchanger(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "Bearer service", r.Header.Get("Authorization"))
+		assert.NoError(t, r.ParseForm())
+
+		if r.PostForm.Get("principal") == "denied" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		assert.Equal(t, url.Values{
+			"grant_type":           {grantTypeTokenExchange},
+			"requested_token_type": {tokenTypeAccessToken},
+			"subject_token":        {"caller"},
+			"subject_token_type":   {tokenTypeAccessToken},
+			"audience":             {"xmidt"},
+			"principal":            {r.PostForm.Get("principal")},
+			"partner_ids":          {"comcast,sky"},
+		}, r.PostForm)
+
+		_, _ = fmt.Fprintf(w, `{"access_token":"exchanged-%d","expires_in":120}`, n)
+	}))
+	defer srv.Close()
+
+	exchanger := &RemoteTokenExchanger{
+		Config: TokenExchangeOptions{
+			URL:      srv.URL,
+			Audience: "xmidt",
+			Timeout:  time.Second,
+			Buffer:   time.Second,
+		},
+		Acquirer: fakeAcquirer{auth: "Bearer service"},
+	}
+
+	subject := Subject{Principal: "user-1", PartnerIDs: []string{"comcast", "sky"}, Token: "caller"}
+
+	actual, err := exchanger.Exchange(context.Background(), subject)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer exchanged-1", actual)
+
+	// the same subject is served from the cache
+	actual, err = exchanger.Exchange(context.Background(), subject)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer exchanged-1", actual)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+
+	// another principal gets its own token
+	other := subject
+	other.Principal = "user-2"
+	actual, err = exchanger.Exchange(context.Background(), other)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer exchanged-2", actual)
+
+	denied := subject
+	denied.Principal = "denied"
+	_, err = exchanger.Exchange(context.Background(), denied)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 403")
+}
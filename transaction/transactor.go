@@ -105,7 +105,13 @@ func New(o *Options) T {
 }
 
 func (t *transactor) Transact(req *http.Request) (result *XmidtResponse, err error) {
-	ctx, cancel := context.WithTimeout(req.Context(), t.RequestTimeout)
+	// a per-request timeout may shorten, but never extend, RequestTimeout
+	timeout := t.RequestTimeout
+	if d, ok := GetRequestTimeout(req.Context()); ok && (d < timeout || timeout <= 0) {
+		timeout = d
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
 	defer cancel()
 
 	var resp *http.Response
@@ -132,6 +138,16 @@ func (t *transactor) Transact(req *http.Request) (result *XmidtResponse, err err
 // Log is used by the different Tr1d1um services to
 // keep track of incoming requests and their corresponding responses
 func Log(reducedLoggingResponseCodes []int) kithttp.ServerFinalizerFunc {
+	return LogFunc(func() []int { return reducedLoggingResponseCodes })
+}
+
+// LogFunc is like Log but reads the reduced logging response codes on every
+// request so they may change at runtime. A nil func means no codes.
+func LogFunc(reducedLoggingResponseCodes func() []int) kithttp.ServerFinalizerFunc {
+	if reducedLoggingResponseCodes == nil {
+		reducedLoggingResponseCodes = func() []int { return nil }
+	}
+
 	return func(ctx context.Context, code int, r *http.Request) {
 		tid, _ := ctx.Value(ContextKeyRequestTID).(string)
 		logger := sallust.Get(ctx)
@@ -153,7 +169,7 @@ func Log(reducedLoggingResponseCodes []int) kithttp.ServerFinalizerFunc {
 		includeHeaders := true
 		response := response{Code: code}
 
-		for _, responseCode := range reducedLoggingResponseCodes {
+		for _, responseCode := range reducedLoggingResponseCodes() {
 			if responseCode == code {
 				includeHeaders = false
 				break
@@ -198,6 +214,16 @@ type FingerprintConfig struct {
 // The returned constructor also enriches the request-scoped logger with
 // credential fingerprint fields per the provided FingerprintConfig.
 func Welcome(cfg FingerprintConfig) func(http.Handler) http.Handler {
+	return WelcomeFunc(func() FingerprintConfig { return cfg })
+}
+
+// WelcomeFunc is like Welcome but reads the FingerprintConfig on every request
+// so it may change at runtime. A nil func means no fingerprinting.
+func WelcomeFunc(cfg func() FingerprintConfig) func(http.Handler) http.Handler {
+	if cfg == nil {
+		cfg = func() FingerprintConfig { return FingerprintConfig{} }
+	}
+
 	return func(delegate http.Handler) http.Handler {
 		return http.HandlerFunc(
 			func(w http.ResponseWriter, r *http.Request) {
@@ -210,7 +236,7 @@ func Welcome(cfg FingerprintConfig) func(http.Handler) http.Handler {
 				ctx := context.WithValue(r.Context(), ContextKeyRequestTID, tid)
 				ctx = context.WithValue(ctx, ContextKeyRequestArrivalTime, time.Now())
 				ctx = addDeviceIdToLog(ctx, r)
-				ctx = addBearerFingerprintToLog(ctx, r, cfg)
+				ctx = addBearerFingerprintToLog(ctx, r, cfg())
 				delegate.ServeHTTP(w, r.WithContext(ctx))
 			})
 	}
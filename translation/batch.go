@@ -0,0 +1,409 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/gorilla/mux"
+	"github.com/xmidt-org/candlelight"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+// Defaults for BatchGetConfig.
+const (
+	DefaultBatchPageSize         = 20
+	DefaultBatchMaxPages         = 50
+	DefaultBatchMaxResponseSize  = 10 * 1024 * 1024
+	DefaultBatchMinWildcardDepth = 2
+)
+
+// MediaTypeNDJSON streams batch GET results as one JSON object per page.
+const MediaTypeNDJSON = "application/x-ndjson"
+
+// Batch GET errors
+var (
+	ErrInvalidPageSize = transaction.NewBadRequestError(errors.New("pageSize must be a positive integer"))
+	ErrTooManyPages    = transaction.NewBadRequestError(errors.New("too many pages, use fewer names or a larger pageSize"))
+)
+
+// errBatchTruncated is reported once the batch response reached its size limit.
+var errBatchTruncated = errors.New("response size limit reached, remaining pages were skipped")
+
+// BatchGetConfig configures the batch GET endpoint, which splits a GET into
+// several WDMP calls and streams back the merged results.
+type BatchGetConfig struct {
+	// PageSize is the default number of names per WDMP call.
+	// (Optional) Defaults to DefaultBatchPageSize.
+	PageSize int
+
+	// MaxPages is the maximum number of WDMP calls of a request.
+	// (Optional) Defaults to DefaultBatchMaxPages.
+	MaxPages int
+
+	// MaxResponseSize is the maximum number of bytes of parameters returned.
+	// Pages past it are skipped and the response is marked as truncated.
+	// (Optional) Defaults to DefaultBatchMaxResponseSize.
+	MaxResponseSize int
+
+	// MinWildcardDepth is the minimum number of levels of wildcard names, e.g.
+	// 2 for "Device.WiFi.". Wildcards aren't split, the whole subtree they
+	// select being returned by a single WDMP call, so broader ones are
+	// rejected.
+	// (Optional) Defaults to DefaultBatchMinWildcardDepth.
+	MinWildcardDepth int
+}
+
+func (c BatchGetConfig) withDefaults() BatchGetConfig {
+	if c.PageSize <= 0 {
+		c.PageSize = DefaultBatchPageSize
+	}
+	if c.MaxPages <= 0 {
+		c.MaxPages = DefaultBatchMaxPages
+	}
+	if c.MaxResponseSize <= 0 {
+		c.MaxResponseSize = DefaultBatchMaxResponseSize
+	}
+	if c.MinWildcardDepth <= 0 {
+		c.MinWildcardDepth = DefaultBatchMinWildcardDepth
+	}
+	return c
+}
+
+type batchGetRequest struct {
+	Pages           []*wrp.Message
+	AuthHeaderValue string
+	NDJSON          bool
+}
+
+type batchGetResponse struct {
+	batchGetRequest
+	maxResponseSize int
+	send            func(context.Context, *wrp.Message) (*transaction.XmidtResponse, error)
+}
+
+// batchPage is the result of a single WDMP call.
+type batchPage struct {
+	Names      []string          `json:"names"`
+	Attributes string            `json:"attributes,omitempty"`
+	StatusCode int               `json:"statusCode"`
+	Message    string            `json:"message,omitempty"`
+	Parameters []json.RawMessage `json:"parameters,omitempty"`
+}
+
+// batchSummary closes a chunked JSON batch response.
+type batchSummary struct {
+	Pages     []batchPage `json:"pages"`
+	Truncated bool        `json:"truncated"`
+}
+
+// batchAttributesPrefix prefixes the query parameters giving the attributes of
+// a single name.
+const batchAttributesPrefix = "attributes."
+
+// parseBatchNames returns the names of a batch GET grouped by their attribute
+// selection. Names come from the comma separated names parameter, using the
+// attributes parameter, from name parameters, without attributes, and from
+// "attributes.{name}={attributes}" parameters, selecting the attributes of a
+// single name.
+func parseBatchNames(query map[string][]string) (map[string][]string, []string, error) {
+	var (
+		groups = make(map[string][]string)
+		order  []string
+	)
+	add := func(name, attributes string) {
+		if _, ok := groups[attributes]; !ok {
+			order = append(order, attributes)
+		}
+		groups[attributes] = append(groups[attributes], name)
+	}
+
+	var attributes string
+	if v := query["attributes"]; len(v) > 0 {
+		attributes = v[0]
+	}
+	for _, names := range query["names"] {
+		for _, name := range strings.Split(names, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				add(name, attributes)
+			}
+		}
+	}
+
+	for _, v := range query["name"] {
+		name := strings.TrimSpace(v)
+		if name == "" {
+			return nil, nil, ErrEmptyNames
+		}
+		add(name, "")
+	}
+
+	// query parameters have no order, sort them for the pages to be stable
+	var keys []string
+	for key := range query {
+		if strings.HasPrefix(key, batchAttributesPrefix) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		name := strings.TrimSpace(strings.TrimPrefix(key, batchAttributesPrefix))
+		if name == "" {
+			return nil, nil, ErrEmptyNames
+		}
+		for _, attributes := range query[key] {
+			if attributes = strings.TrimSpace(attributes); attributes == "" {
+				return nil, nil, transaction.NewBadRequestError(fmt.Errorf("invalid parameter '%s', expected '%s{name}={attributes}'", key, batchAttributesPrefix))
+			}
+			add(name, attributes)
+		}
+	}
+
+	if len(order) == 0 {
+		return nil, nil, ErrEmptyNames
+	}
+	return groups, order, nil
+}
+
+// checkWildcards rejects the wildcard names, ending with a '.', selecting
+// subtrees of fewer than minDepth levels.
+func checkWildcards(names []string, minDepth int) error {
+	for _, name := range names {
+		if strings.HasSuffix(name, ".") && strings.Count(name, ".") < minDepth {
+			return transaction.NewBadRequestError(fmt.Errorf("wildcard name '%s' is too broad, wildcards must have at least %d levels", name, minDepth))
+		}
+	}
+	return nil
+}
+
+// paginate splits names into pages of at most pageSize names. Wildcard names,
+// ending with a '.', select whole subtrees and get a page of their own.
+func paginate(names []string, pageSize int) [][]string {
+	var (
+		pages [][]string
+		page  []string
+	)
+	for _, name := range names {
+		if strings.HasSuffix(name, ".") {
+			pages = append(pages, []string{name})
+			continue
+		}
+		page = append(page, name)
+		if len(page) == pageSize {
+			pages = append(pages, page)
+			page = nil
+		}
+	}
+	if len(page) > 0 {
+		pages = append(pages, page)
+	}
+	return pages
+}
+
+// decodeBatchGetRequest returns a decoder splitting a batch GET into pages.
+func decodeBatchGetRequest(c BatchGetConfig) func(context.Context, *http.Request) (interface{}, error) {
+	c = c.withDefaults()
+
+	return func(ctx context.Context, r *http.Request) (interface{}, error) {
+		query := r.URL.Query()
+
+		pageSize := c.PageSize
+		if v := query.Get("pageSize"); v != "" {
+			var err error
+			if pageSize, err = strconv.Atoi(v); err != nil || pageSize <= 0 {
+				return nil, ErrInvalidPageSize
+			}
+		}
+
+		groups, order, err := parseBatchNames(query)
+		if err != nil {
+			return nil, err
+		}
+
+		var (
+			tid          = getTID(ctx)
+			partnerIDs   = getPartnerIDsDecodeRequest(ctx, r)
+			traceHeaders = getTraceHeaders(r.Header)
+			req          = &batchGetRequest{
+				AuthHeaderValue: r.Header.Get(authHeaderKey),
+				NDJSON:          acceptsNDJSON(r.Header.Get("Accept")),
+			}
+		)
+
+		for _, attributes := range order {
+			if err = checkWildcards(groups[attributes], c.MinWildcardDepth); err != nil {
+				return nil, err
+			}
+			for _, names := range paginate(groups[attributes], pageSize) {
+				if len(req.Pages) == c.MaxPages {
+					return nil, ErrTooManyPages
+				}
+
+				wdmp := &getWDMP{Command: CommandGet, Names: names}
+				if attributes != "" {
+					wdmp.Command, wdmp.Attributes = CommandGetAttrs, attributes
+				}
+				payload, err := json.Marshal(wdmp)
+				if err != nil {
+					return nil, err
+				}
+
+				msg, err := wrap(payload, tid, mux.Vars(r), partnerIDs, slices.Clone(traceHeaders))
+				if err != nil {
+					return nil, err
+				}
+				req.Pages = append(req.Pages, msg)
+			}
+		}
+
+		return req, nil
+	}
+}
+
+func acceptsNDJSON(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, _ := strings.Cut(part, ";")
+		if strings.EqualFold(strings.TrimSpace(mediaType), MediaTypeNDJSON) {
+			return true
+		}
+	}
+	return false
+}
+
+// makeBatchGetEndpoint defers the WDMP calls to the encoder, so that pages are
+// streamed back as they arrive.
+func makeBatchGetEndpoint(s Service, c BatchGetConfig) endpoint.Endpoint {
+	c = c.withDefaults()
+
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*batchGetRequest)
+		return &batchGetResponse{
+			batchGetRequest: *req,
+			maxResponseSize: c.MaxResponseSize,
+			send: func(ctx context.Context, msg *wrp.Message) (*transaction.XmidtResponse, error) {
+				return s.SendWRP(ctx, msg, req.AuthHeaderValue)
+			},
+		}, nil
+	}
+}
+
+// getPage sends a page to the device and returns its result.
+func (b *batchGetResponse) getPage(ctx context.Context, msg *wrp.Message) batchPage {
+	var wdmp getWDMP
+	_ = json.Unmarshal(msg.Payload, &wdmp)
+	page := batchPage{Names: wdmp.Names, Attributes: wdmp.Attributes}
+
+	resp, err := b.send(ctx, msg)
+	if err != nil {
+		page.StatusCode, page.Message = http.StatusInternalServerError, transaction.ErrTr1d1umInternal.Error()
+		var ce transaction.CodedError
+		if errors.As(err, &ce) {
+			page.StatusCode, page.Message = ce.StatusCode(), err.Error()
+		}
+		return page
+	}
+
+	if resp.Code != http.StatusOK {
+		page.StatusCode, page.Message = resp.Code, string(resp.Body)
+		return page
+	}
+
+	var deviceResponse wrp.Message
+	if err = wrp.NewDecoderBytes(resp.Body, wrp.Msgpack).Decode(&deviceResponse); err == nil {
+		err = json.Unmarshal(deviceResponse.Payload, &page)
+	}
+	if err != nil {
+		page.StatusCode, page.Message = http.StatusBadGateway, "invalid device response"
+	}
+
+	return page
+}
+
+// encodeBatchGetResponse gets the pages one after the other and streams the
+// results back, either as one NDJSON line per page or as a single JSON object
+// merging the parameters of all pages.
+func encodeBatchGetResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	b := response.(*batchGetResponse)
+	rc := http.NewResponseController(w)
+
+	contentType := "application/json"
+	if b.NDJSON {
+		contentType = MediaTypeNDJSON
+	}
+	w.Header().Set(contentTypeHeaderKey, contentType)
+	w.Header().Set(candlelight.HeaderWPATIDKeyName, getTID(ctx))
+	w.WriteHeader(http.StatusOK)
+
+	var (
+		summary batchSummary
+		size    int
+		first   = true
+		enc     = json.NewEncoder(w)
+	)
+
+	if !b.NDJSON {
+		if _, err := w.Write([]byte(`{"parameters":[`)); err != nil {
+			return err
+		}
+	}
+
+	for _, msg := range b.Pages {
+		if ctx.Err() != nil {
+			// the client went away
+			return nil
+		}
+
+		page := b.getPage(ctx, msg)
+		for _, p := range page.Parameters {
+			size += len(p)
+		}
+		if size > b.maxResponseSize {
+			summary.Truncated = true
+			break
+		}
+
+		if b.NDJSON {
+			if err := enc.Encode(page); err != nil {
+				return err
+			}
+		} else {
+			for _, p := range page.Parameters {
+				if !first {
+					p = append([]byte{','}, p...)
+				}
+				first = false
+				if _, err := w.Write(p); err != nil {
+					return err
+				}
+			}
+			page.Parameters = nil
+			summary.Pages = append(summary.Pages, page)
+		}
+		_ = rc.Flush()
+	}
+
+	if b.NDJSON {
+		if summary.Truncated {
+			return enc.Encode(batchPage{StatusCode: http.StatusRequestEntityTooLarge, Message: errBatchTruncated.Error()})
+		}
+		return nil
+	}
+
+	closing, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+	// splice the summary fields into the object opened above
+	_, err = w.Write(append([]byte("],"), closing[1:]...))
+	return err
+}
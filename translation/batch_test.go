@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+func TestPaginate(t *testing.T) {
+	assert.Equal(t,
+		[][]string{{"Device.WiFi."}, {"a", "b"}, {"Device.DeviceInfo."}, {"c", "d"}, {"e"}},
+		paginate([]string{"a", "Device.WiFi.", "b", "Device.DeviceInfo.", "c", "d", "e"}, 2),
+	)
+}
+
+func TestDecodeBatchGetRequest(t *testing.T) {
+	tests := []struct {
+		description   string
+		query         string
+		expectedWDMPs []getWDMP
+		expectedErr   error
+	}{
+		{
+			description: "names and attributes",
+			query:       "names=a,b,c&attributes=notify",
+			expectedWDMPs: []getWDMP{
+				{Command: CommandGetAttrs, Names: []string{"a", "b"}, Attributes: "notify"},
+				{Command: CommandGetAttrs, Names: []string{"c"}, Attributes: "notify"},
+			},
+		},
+		{
+			description: "per-name attributes",
+			query:       "name=Device.WiFi.&attributes.a=notify&name=b",
+			expectedWDMPs: []getWDMP{
+				{Command: CommandGet, Names: []string{"Device.WiFi."}},
+				{Command: CommandGet, Names: []string{"b"}},
+				{Command: CommandGetAttrs, Names: []string{"a"}, Attributes: "notify"},
+			},
+		},
+		{
+			description: "broad wildcard",
+			query:       "name=Device.",
+			expectedErr: transaction.NewBadRequestError(errors.New("wildcard name 'Device.' is too broad, wildcards must have at least 2 levels")),
+		},
+		{
+			description: "page size",
+			query:       "names=a,b,c&pageSize=3",
+			expectedWDMPs: []getWDMP{
+				{Command: CommandGet, Names: []string{"a", "b", "c"}},
+			},
+		},
+		{
+			description: "no names",
+			expectedErr: ErrEmptyNames,
+		},
+		{
+			description: "invalid page size",
+			query:       "names=a&pageSize=0",
+			expectedErr: ErrInvalidPageSize,
+		},
+		{
+			description: "too many pages",
+			query:       "names=a,b,c,d,e,f,g",
+			expectedErr: ErrTooManyPages,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			r := httptest.NewRequest(http.MethodGet, "http://localhost/api/v3/device/mac:112233445566/config/batch?"+tc.query, nil)
+			r = mux.SetURLVars(r, map[string]string{"deviceid": "mac:112233445566", "service": "config"})
+
+			decoded, err := decodeBatchGetRequest(BatchGetConfig{PageSize: 2, MaxPages: 3})(ctxTID, r)
+			if tc.expectedErr != nil {
+				assert.Equal(tc.expectedErr, err)
+				return
+			}
+			require.NoError(err)
+
+			req := decoded.(*batchGetRequest)
+			require.Len(req.Pages, len(tc.expectedWDMPs))
+			for i, msg := range req.Pages {
+				var wdmp getWDMP
+				require.NoError(json.Unmarshal(msg.Payload, &wdmp))
+				assert.Equal(tc.expectedWDMPs[i], wdmp)
+				assert.Equal("mac:112233445566/config", msg.Destination)
+				assert.Equal("test-tid", msg.TransactionUUID)
+			}
+		})
+	}
+}
+
+// batchPages returns the WRP messages of a GET for each group of names.
+func batchPages(names ...[]string) []*wrp.Message {
+	var pages []*wrp.Message
+	for _, n := range names {
+		payload, _ := json.Marshal(&getWDMP{Command: CommandGet, Names: n})
+		pages = append(pages, &wrp.Message{Type: wrp.SimpleRequestResponseMessageType, Payload: payload})
+	}
+	return pages
+}
+
+// batchSend answers each page with a parameter per name, named after it.
+func batchSend(_ context.Context, msg *wrp.Message) (*transaction.XmidtResponse, error) {
+	var wdmp getWDMP
+	_ = json.Unmarshal(msg.Payload, &wdmp)
+	if wdmp.Names[0] == "fail" {
+		return nil, errors.New("connection refused")
+	}
+
+	var params []string
+	for _, name := range wdmp.Names {
+		params = append(params, `{"name":"`+name+`","value":"v"}`)
+	}
+	payload := `{"statusCode":200,"message":"Success","parameters":[` + strings.Join(params, ",") + `]}`
+
+	return &transaction.XmidtResponse{
+		Code: http.StatusOK,
+		Body: wrp.MustEncode(&wrp.Message{Type: wrp.SimpleRequestResponseMessageType, Payload: []byte(payload)}, wrp.Msgpack),
+	}, nil
+}
+
+func TestEncodeBatchGetResponse(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		assert := assert.New(t)
+		w := httptest.NewRecorder()
+
+		err := encodeBatchGetResponse(ctxTID, w, &batchGetResponse{
+			batchGetRequest: batchGetRequest{Pages: batchPages([]string{"a", "b"}, []string{"fail"}, []string{"c"})},
+			maxResponseSize: DefaultBatchMaxResponseSize,
+			send:            batchSend,
+		})
+
+		assert.NoError(err)
+		assert.Equal(http.StatusOK, w.Code)
+		assert.Equal("application/json", w.Header().Get(contentTypeHeaderKey))
+		assert.JSONEq(`{
+			"parameters": [
+				{"name": "a", "value": "v"},
+				{"name": "b", "value": "v"},
+				{"name": "c", "value": "v"}
+			],
+			"pages": [
+				{"names": ["a", "b"], "statusCode": 200, "message": "Success"},
+				{"names": ["fail"], "statusCode": 500, "message": "oops! Something unexpected went wrong in this service"},
+				{"names": ["c"], "statusCode": 200, "message": "Success"}
+			],
+			"truncated": false
+		}`, w.Body.String())
+	})
+
+	t.Run("NDJSON truncated", func(t *testing.T) {
+		assert := assert.New(t)
+		w := httptest.NewRecorder()
+
+		err := encodeBatchGetResponse(ctxTID, w, &batchGetResponse{
+			batchGetRequest: batchGetRequest{Pages: batchPages([]string{"a"}, []string{"b"}), NDJSON: true},
+			maxResponseSize: 30,
+			send:            batchSend,
+		})
+
+		assert.NoError(err)
+		assert.Equal(MediaTypeNDJSON, w.Header().Get(contentTypeHeaderKey))
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		if assert.Len(lines, 2) {
+			assert.JSONEq(`{"names":["a"],"statusCode":200,"message":"Success","parameters":[{"name":"a","value":"v"}]}`, lines[0])
+			assert.JSONEq(`{"names":null,"statusCode":413,"message":"response size limit reached, remaining pages were skipped"}`, lines[1])
+		}
+	})
+}
@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/xmidt-org/tr1d1um/transaction"
+)
+
+// DefaultBodyLimit is the default maximum size, in bytes, of request bodies.
+const DefaultBodyLimit = 1024 * 1024
+
+// ErrBodyTooLarge is returned for request bodies over their size limit.
+var ErrBodyTooLarge = transaction.NewCodedError(errors.New("request body too large"), http.StatusRequestEntityTooLarge)
+
+// BodyLimits bounds the size of request bodies.
+type BodyLimits struct {
+	// Default is the maximum size, in bytes, of request bodies.
+	// (Optional) Defaults to DefaultBodyLimit.
+	Default int64
+
+	// Methods maps an HTTP method, such as "PATCH", to its maximum body size,
+	// in bytes.
+	// (Optional)
+	Methods map[string]int64
+}
+
+// Limit returns the maximum body size, in bytes, of requests with the given
+// method.
+func (l BodyLimits) Limit(method string) int64 {
+	for m, limit := range l.Methods {
+		if strings.EqualFold(m, method) && limit > 0 {
+			return limit
+		}
+	}
+	if l.Default > 0 {
+		return l.Default
+	}
+	return DefaultBodyLimit
+}
+
+type requestBody struct {
+	data []byte
+	err  error
+}
+
+// captureRequestBody reads the request body once, up to its limit, and puts it
+// in the request context for logging and decoding to share. Bodies over the
+// limit are left for decodeRequestBody to reject.
+func captureRequestBody(limits BodyLimits) kithttp.RequestFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		if r.Body == nil || r.Body == http.NoBody {
+			return ctx
+		}
+
+		limit := limits.Limit(r.Method)
+		data, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+		r.Body.Close()
+		if err == nil && int64(len(data)) > limit {
+			err = ErrBodyTooLarge
+		} else if err != nil {
+			err = ErrInvalidPayload
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(data))
+		return context.WithValue(ctx, contextKeyRequestBody, requestBody{data: data, err: err})
+	}
+}
+
+// decodeRequestBody rejects requests whose body couldn't be captured before
+// decoding them with decoder.
+func decodeRequestBody(decoder kithttp.DecodeRequestFunc) kithttp.DecodeRequestFunc {
+	return func(ctx context.Context, r *http.Request) (interface{}, error) {
+		if body, ok := ctx.Value(contextKeyRequestBody).(requestBody); ok && body.err != nil {
+			return nil, body.err
+		}
+		return decoder(ctx, r)
+	}
+}
+
+// getRequestBody returns the request body captured by captureRequestBody.
+func getRequestBody(ctx context.Context) ([]byte, bool) {
+	body, ok := ctx.Value(contextKeyRequestBody).(requestBody)
+	if !ok || body.err != nil {
+		return nil, false
+	}
+	return body.data, true
+}
+
+// strictUnmarshal is json.Unmarshal rejecting unknown struct fields and
+// duplicate object keys.
+func strictUnmarshal(data []byte, v interface{}) error {
+	if err := checkDuplicateKeys(json.NewDecoder(bytes.NewReader(data))); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("invalid character after top-level value")
+	}
+	return nil
+}
+
+// checkDuplicateKeys walks the next JSON value of dec, failing on objects
+// with the same key more than once.
+func checkDuplicateKeys(dec *json.Decoder) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch t {
+	case json.Delim('{'):
+		keys := make(map[string]bool)
+		for dec.More() {
+			if t, err = dec.Token(); err != nil {
+				return err
+			}
+			key, _ := t.(string)
+			if keys[key] {
+				return fmt.Errorf("duplicate key '%s'", key)
+			}
+			keys[key] = true
+
+			if err = checkDuplicateKeys(dec); err != nil {
+				return err
+			}
+		}
+	case json.Delim('['):
+		for dec.More() {
+			if err = checkDuplicateKeys(dec); err != nil {
+				return err
+			}
+		}
+	default:
+		return nil
+	}
+
+	// the closing delimiter
+	_, err = dec.Token()
+	return err
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBodyLimitsLimit(t *testing.T) {
+	limits := BodyLimits{Default: 100, Methods: map[string]int64{"patch": 10}}
+	assert.Equal(t, int64(10), limits.Limit(http.MethodPatch))
+	assert.Equal(t, int64(100), limits.Limit(http.MethodPut))
+	assert.Equal(t, int64(DefaultBodyLimit), BodyLimits{}.Limit(http.MethodPost))
+}
+
+func TestCaptureRequestBody(t *testing.T) {
+	capture := captureRequestBody(BodyLimits{Default: 8})
+	decode := decodeRequestBody(func(_ context.Context, r *http.Request) (interface{}, error) {
+		return io.ReadAll(r.Body)
+	})
+
+	t.Run("within limit", func(t *testing.T) {
+		assert := assert.New(t)
+		r := httptest.NewRequest(http.MethodPatch, "http://localhost", bytes.NewBufferString("12345678"))
+
+		ctx := capture(context.Background(), r)
+		body, ok := getRequestBody(ctx)
+		assert.True(ok)
+		assert.Equal([]byte("12345678"), body)
+
+		// the body can still be read by the decoder
+		decoded, err := decode(ctx, r)
+		assert.NoError(err)
+		assert.Equal([]byte("12345678"), decoded)
+	})
+
+	t.Run("over limit", func(t *testing.T) {
+		assert := assert.New(t)
+		r := httptest.NewRequest(http.MethodPatch, "http://localhost", bytes.NewBufferString("123456789"))
+
+		ctx := capture(context.Background(), r)
+		_, ok := getRequestBody(ctx)
+		assert.False(ok)
+
+		_, err := decode(ctx, r)
+		assert.Equal(ErrBodyTooLarge, err)
+	})
+
+	t.Run("no body", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		_, ok := getRequestBody(capture(context.Background(), r))
+		assert.False(t, ok)
+	})
+}
+
+func TestStrictUnmarshal(t *testing.T) {
+	tests := []struct {
+		description string
+		data        string
+		expectedErr string
+	}{
+		{
+			description: "valid",
+			data:        `{"command": "SET", "parameters": [{"name": "n", "attributes": {"notify": 1}}]}`,
+		},
+		{
+			description: "unknown field",
+			data:        `{"command": "SET", "paramters": []}`,
+			expectedErr: `json: unknown field "paramters"`,
+		},
+		{
+			description: "duplicate key",
+			data:        `{"command": "SET", "command": "GET"}`,
+			expectedErr: "duplicate key 'command'",
+		},
+		{
+			description: "nested duplicate key",
+			data:        `{"parameters": [{"name": "a"}, {"name": "b", "name": "c"}]}`,
+			expectedErr: "duplicate key 'name'",
+		},
+		{
+			description: "trailing data",
+			data:        `{"command": "SET"} {}`,
+			expectedErr: "invalid character after top-level value",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			err := strictUnmarshal([]byte(tc.data), new(setWDMP))
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestLoadWDMPStrict(t *testing.T) {
+	_, err := loadWDMP([]byte(`{"parameters": [{"name": "n", "value": "v", "dataType": 0, "extra": true}]}`), "", "", "")
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "invalid WDMP structure"))
+}
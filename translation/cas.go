@@ -0,0 +1,218 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/gorilla/mux"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+// Compare-and-swap headers. A PATCH with "X-Webpa-Sync-Mode: cas" has its
+// SET applied with TEST_AND_SET against the CID the device currently has.
+// The final CID of the device is returned in the X-Webpa-Sync-Cid header.
+const (
+	HeaderWPASyncMode = "X-Webpa-Sync-Mode"
+	HeaderWPASyncCID  = "X-Webpa-Sync-Cid"
+
+	SyncModeCAS = "cas"
+)
+
+// Defaults for CompareAndSwapConfig.
+const (
+	DefaultCIDParameter = "Device.DeviceInfo.Webpa.X_COMCAST-COM_CID"
+	DefaultCASRetries   = 3
+
+	// DefaultCIDConflictStatusCode is the WDMP status code of a TEST_AND_SET
+	// whose old CID doesn't match the CID of the device.
+	DefaultCIDConflictStatusCode = 550
+)
+
+// Compare-and-swap errors
+var (
+	ErrOldCIDNotAllowed   = transaction.NewBadRequestError(errors.New("X-Webpa-Sync-Old-Cid is fetched from the device in compare-and-swap mode"))
+	ErrInvalidCIDResponse = transaction.NewCodedError(errors.New("invalid CID response from device"), http.StatusBadGateway)
+)
+
+// CompareAndSwapConfig configures the compare-and-swap mode of SET requests.
+type CompareAndSwapConfig struct {
+	// CIDParameter is the device parameter holding the CID.
+	// (Optional) Defaults to DefaultCIDParameter.
+	CIDParameter string
+
+	// Retries is the maximum number of times a TEST_AND_SET failing with a CID
+	// conflict is retried with a freshly fetched CID.
+	// (Optional) Defaults to DefaultCASRetries, 0 disables the retries.
+	Retries *int
+
+	// ConflictStatusCodes lists the WDMP status codes of CID conflicts.
+	// (Optional) Defaults to DefaultCIDConflictStatusCode.
+	ConflictStatusCodes []int
+}
+
+func (c CompareAndSwapConfig) withDefaults() CompareAndSwapConfig {
+	if c.CIDParameter == "" {
+		c.CIDParameter = DefaultCIDParameter
+	}
+	if c.Retries == nil {
+		retries := DefaultCASRetries
+		c.Retries = &retries
+	}
+	if len(c.ConflictStatusCodes) == 0 {
+		c.ConflictStatusCodes = []int{DefaultCIDConflictStatusCode}
+	}
+	return c
+}
+
+type casRequest struct {
+	WDMP            *setWDMP
+	AuthHeaderValue string
+
+	// wrap wraps a WDMP payload into a WRP message to the device.
+	wrap func([]byte) (*wrp.Message, error)
+}
+
+type casResponse struct {
+	*transaction.XmidtResponse
+
+	// CID is the CID of the device after the request, if known.
+	CID string
+}
+
+// genCID generates a random CID.
+func genCID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// decodeCASRequest decodes a SET to apply with TEST_AND_SET. The new CID
+// comes from the X-Webpa-Sync-New-Cid header or is generated.
+func decodeCASRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	if r.Header.Get(HeaderWPASyncOldCID) != "" {
+		return nil, ErrOldCIDNotAllowed
+	}
+
+	newCID := r.Header.Get(HeaderWPASyncNewCID)
+	if newCID == "" {
+		newCID = genCID()
+	}
+
+	data, _ := getRequestBody(ctx)
+	wdmp, err := loadWDMP(data, newCID, "", r.Header.Get(HeaderWPASyncCMC))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		tid          = getTID(ctx)
+		partnerIDs   = getPartnerIDsDecodeRequest(ctx, r)
+		traceHeaders = getTraceHeaders(r.Header)
+		vars         = mux.Vars(r)
+	)
+	if _, err = wrp.ParseDeviceID(vars["deviceid"]); err != nil {
+		return nil, transaction.NewBadRequestError(err)
+	}
+
+	return &casRequest{
+		WDMP:            wdmp,
+		AuthHeaderValue: r.Header.Get(authHeaderKey),
+		wrap: func(payload []byte) (*wrp.Message, error) {
+			return wrap(payload, tid, vars, partnerIDs, slices.Clone(traceHeaders))
+		},
+	}, nil
+}
+
+// makeCASEndpoint fetches the CID of the device and applies the TEST_AND_SET
+// with it, fetching the CID again and retrying on CID conflicts.
+func makeCASEndpoint(s Service, c CompareAndSwapConfig) endpoint.Endpoint {
+	c = c.withDefaults()
+
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*casRequest)
+		send := func(v interface{}) (*transaction.XmidtResponse, error) {
+			payload, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			msg, err := req.wrap(payload)
+			if err != nil {
+				return nil, err
+			}
+			return s.SendWRP(ctx, msg, req.AuthHeaderValue)
+		}
+
+		for attempt := 0; ; attempt++ {
+			resp, err := send(&getWDMP{Command: CommandGet, Names: []string{c.CIDParameter}})
+			if err != nil {
+				return nil, err
+			}
+			result, payload := DeviceResult(resp, nil)
+			if !succeeded(result) {
+				// the device couldn't tell its CID
+				return &casResponse{XmidtResponse: resp}, nil
+			}
+
+			cid, err := parseCID(payload, c.CIDParameter)
+			if err != nil {
+				return nil, err
+			}
+
+			req.WDMP.OldCid = cid
+			if resp, err = send(req.WDMP); err != nil {
+				return nil, err
+			}
+
+			result, _ = DeviceResult(resp, nil)
+			switch {
+			case succeeded(result):
+				return &casResponse{XmidtResponse: resp, CID: req.WDMP.NewCid}, nil
+			case !slices.Contains(c.ConflictStatusCodes, result.StatusCode):
+				return &casResponse{XmidtResponse: resp, CID: cid}, nil
+			case attempt >= *c.Retries:
+				// the CID of the device keeps changing
+				return &casResponse{XmidtResponse: resp}, nil
+			}
+		}
+	}
+}
+
+// parseCID returns the CID from the response to a GET of the CID parameter.
+func parseCID(payload []byte, parameter string) (string, error) {
+	var getResponse struct {
+		Parameters []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"parameters"`
+	}
+	if err := json.Unmarshal(payload, &getResponse); err != nil {
+		return "", ErrInvalidCIDResponse
+	}
+
+	for _, p := range getResponse.Parameters {
+		if p.Name == parameter {
+			return p.Value, nil
+		}
+	}
+	return "", ErrInvalidCIDResponse
+}
+
+// encodeCASResponse sets the X-Webpa-Sync-Cid header before encoding the
+// device response as any other.
+func encodeCASResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(*casResponse)
+	if resp.CID != "" {
+		w.Header().Set(HeaderWPASyncCID, resp.CID)
+	}
+	return encodeResponse(ctx, w, resp.XmidtResponse)
+}
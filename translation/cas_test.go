@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+// casDevice holds a CID, which another client changes right after each of
+// the first conflicts GETs of it.
+type casDevice struct {
+	cid       string
+	conflicts int
+	gets      int
+	sets      int
+}
+
+func (d *casDevice) SendWRP(_ context.Context, msg *wrp.Message, _ string) (*transaction.XmidtResponse, error) {
+	var wdmp setWDMP
+	_ = json.Unmarshal(msg.Payload, &wdmp)
+
+	var payload string
+	switch wdmp.Command {
+	case CommandGet:
+		d.gets++
+		payload = fmt.Sprintf(`{"statusCode": 200, "parameters": [{"name": "%s", "value": "%s", "dataType": 0}]}`, DefaultCIDParameter, d.cid)
+		if d.gets <= d.conflicts {
+			d.cid = fmt.Sprintf("other-%d", d.gets)
+		}
+	case CommandTestSet:
+		d.sets++
+		payload = `{"statusCode": 550, "message": "CID test failed"}`
+		if wdmp.OldCid == d.cid {
+			d.cid = wdmp.NewCid
+			payload = `{"statusCode": 200, "message": "Success"}`
+		}
+	}
+
+	return &transaction.XmidtResponse{
+		Code: http.StatusOK,
+		Body: wrp.MustEncode(&wrp.Message{Type: wrp.SimpleRequestResponseMessageType, Payload: []byte(payload)}, wrp.Msgpack),
+	}, nil
+}
+
+func decodeTestCAS(header http.Header) (interface{}, error) {
+	r := httptest.NewRequest(http.MethodPatch, "http://localhost/api/v3/device/mac:112233445566/config",
+		bytes.NewBufferString(`{"parameters": [{"name": "Device.A", "value": "v", "dataType": 0}]}`))
+	r = mux.SetURLVars(r, map[string]string{"deviceid": "mac:112233445566", "service": "config"})
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	return decodeCASRequest(captureRequestBody(BodyLimits{})(ctxTID, r), r)
+}
+
+func TestDecodeCASRequest(t *testing.T) {
+	t.Run("new CID", func(t *testing.T) {
+		decoded, err := decodeTestCAS(http.Header{HeaderWPASyncNewCID: {"cid-2"}, HeaderWPASyncCMC: {"1"}})
+		require.NoError(t, err)
+
+		wdmp := decoded.(*casRequest).WDMP
+		assert.Equal(t, CommandTestSet, wdmp.Command)
+		assert.Equal(t, "cid-2", wdmp.NewCid)
+		assert.Equal(t, "1", wdmp.SyncCmc)
+	})
+
+	t.Run("generated CID", func(t *testing.T) {
+		decoded, err := decodeTestCAS(nil)
+		require.NoError(t, err)
+		assert.Len(t, decoded.(*casRequest).WDMP.NewCid, 32)
+	})
+
+	t.Run("old CID", func(t *testing.T) {
+		_, err := decodeTestCAS(http.Header{HeaderWPASyncOldCID: {"cid-1"}})
+		assert.Equal(t, ErrOldCIDNotAllowed, err)
+	})
+}
+
+func TestCASEndpoint(t *testing.T) {
+	tests := []struct {
+		description  string
+		retries      int
+		conflicts    int
+		expectedSets int
+		expectedCode int
+		expectedCID  string
+	}{
+		{
+			description:  "no conflict",
+			retries:      2,
+			expectedSets: 1,
+			expectedCode: http.StatusOK,
+			expectedCID:  "cid-2",
+		},
+		{
+			description:  "retried conflicts",
+			retries:      2,
+			conflicts:    2,
+			expectedSets: 3,
+			expectedCode: http.StatusOK,
+			expectedCID:  "cid-2",
+		},
+		{
+			description:  "too many conflicts",
+			retries:      2,
+			conflicts:    5,
+			expectedSets: 3,
+			expectedCode: 550,
+		},
+		{
+			description:  "no retries",
+			conflicts:    1,
+			expectedSets: 1,
+			expectedCode: 550,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			req, err := decodeTestCAS(http.Header{HeaderWPASyncNewCID: {"cid-2"}})
+			require.NoError(err)
+
+			device := &casDevice{cid: "cid-1", conflicts: tc.conflicts}
+			resp, err := makeCASEndpoint(device, CompareAndSwapConfig{Retries: &tc.retries})(ctxTID, req)
+			require.NoError(err)
+			assert.Equal(tc.expectedSets, device.sets)
+
+			w := httptest.NewRecorder()
+			require.NoError(encodeCASResponse(captureResponseMediaType(ctxTID, httptest.NewRequest(http.MethodPatch, "http://localhost", nil)), w, resp))
+			assert.Equal(tc.expectedCode, w.Code)
+			assert.Equal(tc.expectedCID, w.Header().Get(HeaderWPASyncCID))
+		})
+	}
+}
+
+func TestParseCID(t *testing.T) {
+	cid, err := parseCID([]byte(`{"parameters": [{"name": "Device.CID", "value": "abc"}]}`), "Device.CID")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", cid)
+
+	_, err = parseCID([]byte(`{"parameters": []}`), "Device.CID")
+	assert.Equal(t, ErrInvalidCIDResponse, err)
+}
@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/xmidt-org/candlelight"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+// DefaultMaxEventDevices is the default maximum number of devices a single
+// event request may target.
+const DefaultMaxEventDevices = 100
+
+// Outcome label values for EventMetrics.
+const (
+	EventAccepted = "accepted"
+	EventRejected = "rejected"
+	EventFailed   = "failed"
+)
+
+// Event request errors
+var (
+	ErrMissingEventDevices = transaction.NewBadRequestError(errors.New("at least one deviceid query parameter is required"))
+	ErrTooManyEventDevices = transaction.NewBadRequestError(errors.New("too many devices"))
+)
+
+// EventConfig configures the fire-and-forget event endpoints.
+type EventConfig struct {
+	// Services lists the device services events may be sent to.
+	Services []string
+
+	// MaxDevices is the maximum number of devices a single request may target.
+	// (Optional) Defaults to DefaultMaxEventDevices.
+	MaxDevices int
+}
+
+// EventMetrics records the delivery of events to XMiDT.
+// Nil fields are skipped.
+type EventMetrics struct {
+	// Sent counts events sent to XMiDT by service and outcome.
+	Sent *prometheus.CounterVec
+}
+
+func (m EventMetrics) observe(service, outcome string) {
+	if m.Sent != nil {
+		m.Sent.WithLabelValues(service, outcome).Inc()
+	}
+}
+
+type eventRequest struct {
+	Service         string
+	Messages        []*wrp.Message
+	AuthHeaderValue string
+}
+
+// eventResult is the outcome of sending an event to a single device.
+type eventResult struct {
+	StatusCode int    `json:"statusCode"`
+	Message    string `json:"message,omitempty"`
+}
+
+type eventResponse struct {
+	Devices map[string]eventResult `json:"devices"`
+}
+
+// decodeEventRequest returns a decoder for events sent to the device in the
+// path, or to the devices listed in the deviceid query parameters.
+func decodeEventRequest(maxDevices int) func(context.Context, *http.Request) (interface{}, error) {
+	if maxDevices <= 0 {
+		maxDevices = DefaultMaxEventDevices
+	}
+
+	return func(ctx context.Context, r *http.Request) (interface{}, error) {
+		vars := mux.Vars(r)
+
+		deviceIDs := r.URL.Query()["deviceid"]
+		if id, ok := vars["deviceid"]; ok {
+			deviceIDs = []string{id}
+		}
+		if len(deviceIDs) == 0 {
+			return nil, ErrMissingEventDevices
+		}
+
+		payload, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, ErrInvalidPayload
+		}
+
+		contentType := r.Header.Get(contentTypeHeaderKey)
+		if contentType == "" {
+			contentType = wrp.MimeTypeOctetStream
+		}
+
+		var (
+			tid          = getTID(ctx)
+			partnerIDs   = getPartnerIDsDecodeRequest(ctx, r)
+			traceHeaders = getTraceHeaders(r.Header)
+			seen         = make(map[wrp.DeviceID]bool, len(deviceIDs))
+			messages     = make([]*wrp.Message, 0, len(deviceIDs))
+		)
+
+		for _, id := range deviceIDs {
+			deviceID, err := wrp.ParseDeviceID(id)
+			if err != nil {
+				return nil, transaction.NewBadRequestError(err)
+			}
+			if seen[deviceID] {
+				continue
+			}
+			seen[deviceID] = true
+
+			messages = append(messages, &wrp.Message{
+				Type:            wrp.SimpleEventMessageType,
+				Destination:     fmt.Sprintf("%s/%s", string(deviceID), vars["service"]),
+				TransactionUUID: tid,
+				PartnerIDs:      partnerIDs,
+				Headers:         slices.Clone(traceHeaders),
+				Payload:         payload,
+				ContentType:     contentType,
+			})
+		}
+
+		// duplicates don't count toward the limit
+		if len(messages) > maxDevices {
+			return nil, ErrTooManyEventDevices
+		}
+
+		return &eventRequest{
+			Service:         vars["service"],
+			Messages:        messages,
+			AuthHeaderValue: r.Header.Get(authHeaderKey),
+		}, nil
+	}
+}
+
+// makeEventEndpoint sends the events of a request to XMiDT concurrently.
+func makeEventEndpoint(s Service, m EventMetrics) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		eventReq := request.(*eventRequest)
+
+		var (
+			mu   sync.Mutex
+			wg   sync.WaitGroup
+			resp = &eventResponse{Devices: make(map[string]eventResult, len(eventReq.Messages))}
+		)
+
+		for _, msg := range eventReq.Messages {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				deviceID, _, _ := strings.Cut(msg.Destination, "/")
+				result, outcome := sendEvent(ctx, s, msg, eventReq.AuthHeaderValue)
+				m.observe(eventReq.Service, outcome)
+
+				mu.Lock()
+				resp.Devices[deviceID] = result
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		return resp, nil
+	}
+}
+
+func sendEvent(ctx context.Context, s Service, msg *wrp.Message, authHeaderValue string) (eventResult, string) {
+	resp, err := s.SendWRP(ctx, msg, authHeaderValue)
+	if err != nil {
+		var ce transaction.CodedError
+		if errors.As(err, &ce) {
+			return eventResult{StatusCode: ce.StatusCode(), Message: err.Error()}, EventFailed
+		}
+		return eventResult{StatusCode: http.StatusInternalServerError, Message: transaction.ErrTr1d1umInternal.Error()}, EventFailed
+	}
+
+	if resp.Code >= http.StatusMultipleChoices {
+		return eventResult{StatusCode: resp.Code, Message: string(resp.Body)}, EventRejected
+	}
+
+	return eventResult{StatusCode: http.StatusAccepted}, EventAccepted
+}
+
+// encodeEventResponse responds with a 202 once XMiDT accepted every event.
+// Otherwise, it uses the status of the failed event, or a 207 when some of
+// several events failed.
+func encodeEventResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(*eventResponse)
+
+	code := http.StatusAccepted
+	for _, result := range resp.Devices {
+		if result.StatusCode == http.StatusAccepted {
+			continue
+		}
+		code = result.StatusCode
+		if len(resp.Devices) > 1 {
+			code = http.StatusMultiStatus
+		}
+		break
+	}
+
+	w.Header().Set(contentTypeHeaderKey, "application/json")
+	w.Header().Set(candlelight.HeaderWPATIDKeyName, getTID(ctx))
+	w.WriteHeader(code)
+	return json.NewEncoder(w).Encode(resp)
+}
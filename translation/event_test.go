@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+func TestDecodeEventRequest(t *testing.T) {
+	tests := []struct {
+		description         string
+		url                 string
+		vars                map[string]string
+		expectedDestination []string
+		expectedErr         error
+	}{
+		{
+			description:         "single device",
+			url:                 "http://localhost/api/v3/event/device/mac:112233445566/notify",
+			vars:                map[string]string{"deviceid": "mac:112233445566", "service": "notify"},
+			expectedDestination: []string{"mac:112233445566/notify"},
+		},
+		{
+			description:         "devices",
+			url:                 "http://localhost/api/v3/event/devices/notify?deviceid=mac:112233445566&deviceid=mac:aabbccddeeff&deviceid=MAC:11-22-33-44-55-66",
+			vars:                map[string]string{"service": "notify"},
+			expectedDestination: []string{"mac:112233445566/notify", "mac:aabbccddeeff/notify"},
+		},
+		{
+			description: "no devices",
+			url:         "http://localhost/api/v3/event/devices/notify",
+			vars:        map[string]string{"service": "notify"},
+			expectedErr: ErrMissingEventDevices,
+		},
+		{
+			description: "too many devices",
+			url:         "http://localhost/api/v3/event/devices/notify?deviceid=mac:112233445566&deviceid=mac:aabbccddeeff&deviceid=mac:ffffffffffff",
+			vars:        map[string]string{"service": "notify"},
+			expectedErr: ErrTooManyEventDevices,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			r := httptest.NewRequest(http.MethodPost, tc.url, bytes.NewBufferString("reboot"))
+			r = mux.SetURLVars(r, tc.vars)
+			r.Header.Set(contentTypeHeaderKey, "text/plain")
+
+			decoded, err := decodeEventRequest(2)(ctxTID, r)
+			if tc.expectedErr != nil {
+				assert.Equal(tc.expectedErr, err)
+				return
+			}
+			require.NoError(err)
+
+			req := decoded.(*eventRequest)
+			assert.Equal("notify", req.Service)
+			require.Len(req.Messages, len(tc.expectedDestination))
+			for i, msg := range req.Messages {
+				assert.Equal(wrp.SimpleEventMessageType, msg.Type)
+				assert.Equal(tc.expectedDestination[i], msg.Destination)
+				assert.Equal("test-tid", msg.TransactionUUID)
+				assert.Equal([]byte("reboot"), msg.Payload)
+				assert.Equal("text/plain", msg.ContentType)
+			}
+		})
+	}
+
+	t.Run("invalid device ID", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "http://localhost/api/v3/event/devices/notify?deviceid=bad", nil)
+		r = mux.SetURLVars(r, map[string]string{"service": "notify"})
+
+		_, err := decodeEventRequest(0)(ctxTID, r)
+		var coded transaction.CodedError
+		require.ErrorAs(t, err, &coded)
+		assert.Equal(t, http.StatusBadRequest, coded.StatusCode())
+	})
+}
+
+func TestEventEndpoint(t *testing.T) {
+	tests := []struct {
+		description  string
+		responses    map[string]*transaction.XmidtResponse
+		errs         map[string]error
+		expectedCode int
+	}{
+		{
+			description: "single device accepted",
+			responses: map[string]*transaction.XmidtResponse{
+				"mac:112233445566/notify": {Code: http.StatusOK},
+			},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			description: "single device rejected",
+			responses: map[string]*transaction.XmidtResponse{
+				"mac:112233445566/notify": {Code: http.StatusNotFound, Body: []byte("device not found")},
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			description: "devices accepted",
+			responses: map[string]*transaction.XmidtResponse{
+				"mac:112233445566/notify": {Code: http.StatusOK},
+				"mac:aabbccddeeff/notify": {Code: http.StatusAccepted},
+			},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			description: "devices partially failed",
+			responses: map[string]*transaction.XmidtResponse{
+				"mac:112233445566/notify": {Code: http.StatusOK},
+			},
+			errs: map[string]error{
+				"mac:aabbccddeeff/notify": errors.New("connection refused"),
+			},
+			expectedCode: http.StatusMultiStatus,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			s := new(MockService)
+			req := &eventRequest{Service: "notify", AuthHeaderValue: "Basic xyz=="}
+			for destination, resp := range tc.responses {
+				msg := &wrp.Message{Type: wrp.SimpleEventMessageType, Destination: destination}
+				req.Messages = append(req.Messages, msg)
+				s.On("SendWRP", mock.Anything, msg, "Basic xyz==").Return(resp, nil)
+			}
+			for destination, err := range tc.errs {
+				msg := &wrp.Message{Type: wrp.SimpleEventMessageType, Destination: destination}
+				req.Messages = append(req.Messages, msg)
+				s.On("SendWRP", mock.Anything, msg, "Basic xyz==").Return(nil, err)
+			}
+
+			sent := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "events_sent"}, []string{"service", "outcome"})
+			resp, err := makeEventEndpoint(s, EventMetrics{Sent: sent})(ctxTID, req)
+			require.NoError(err)
+			s.AssertExpectations(t)
+
+			w := httptest.NewRecorder()
+			require.NoError(encodeEventResponse(ctxTID, w, resp))
+			assert.Equal(tc.expectedCode, w.Code)
+			assert.Equal("application/json", w.Header().Get(contentTypeHeaderKey))
+
+			var accepted int
+			for _, r := range tc.responses {
+				if r.Code < http.StatusMultipleChoices {
+					accepted++
+				}
+			}
+			assert.Equal(float64(accepted), testutil.ToFloat64(sent.WithLabelValues("notify", EventAccepted)))
+			assert.Equal(float64(len(tc.errs)), testutil.ToFloat64(sent.WithLabelValues("notify", EventFailed)))
+		})
+	}
+}
@@ -74,7 +74,7 @@ type mockAcquirer struct {
 	mock.Mock
 }
 
-func (m *mockAcquirer) Acquire() (string, error) {
+func (m *mockAcquirer) Acquire(context.Context) (string, error) {
 	args := m.Called()
 	return args.String(0), args.Error(1)
 }
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+// Media types clients may accept for device responses.
+const (
+	// MediaTypePayload is the device response payload alone, the default.
+	MediaTypePayload = "application/json"
+
+	// MediaTypeWRPJSON is the full WRP response message in JSON.
+	MediaTypeWRPJSON = "application/wrp+json"
+
+	// MediaTypeWRPMsgpack is the full WRP response message in msgpack.
+	MediaTypeWRPMsgpack = wrp.MimeTypeMsgpack
+)
+
+// ErrNotAcceptable is returned when none of the accepted media types is
+// supported and unacceptable requests are rejected.
+var ErrNotAcceptable = transaction.NewCodedError(
+	errors.New("not acceptable, supported media types are "+MediaTypePayload+", "+MediaTypeWRPJSON+" and "+MediaTypeWRPMsgpack),
+	http.StatusNotAcceptable,
+)
+
+// negotiateMediaType returns the supported media type preferred by an Accept
+// header. Media types are preferred by quality, then by order.
+func negotiateMediaType(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return MediaTypePayload, true
+	}
+
+	var (
+		best    string
+		bestQ   = 0.0
+		matched bool
+	)
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= 0 || (matched && q <= bestQ) {
+			continue
+		}
+
+		var supported string
+		switch mediaType {
+		case MediaTypePayload, "*/*", "application/*":
+			supported = MediaTypePayload
+		case MediaTypeWRPJSON:
+			supported = MediaTypeWRPJSON
+		case MediaTypeWRPMsgpack, wrp.MimeTypeWrp:
+			supported = MediaTypeWRPMsgpack
+		default:
+			continue
+		}
+
+		best, bestQ, matched = supported, q, true
+	}
+
+	return best, matched
+}
+
+// captureResponseMediaType puts the media type negotiated for the response in
+// the request context. Unacceptable requests get the payload alone, unless
+// decodeAcceptableRequest rejects them.
+func captureResponseMediaType(ctx context.Context, r *http.Request) context.Context {
+	if mediaType, ok := negotiateMediaType(r.Header.Get("Accept")); ok {
+		return context.WithValue(ctx, contextKeyResponseMediaType, mediaType)
+	}
+	return ctx
+}
+
+// decodeAcceptableRequest rejects requests accepting none of the supported
+// media types before they are sent to the device, when strict is set.
+func decodeAcceptableRequest(strict bool, decoder kithttp.DecodeRequestFunc) kithttp.DecodeRequestFunc {
+	if !strict {
+		return decoder
+	}
+	return func(ctx context.Context, r *http.Request) (interface{}, error) {
+		if _, ok := negotiateMediaType(r.Header.Get("Accept")); !ok {
+			return nil, ErrNotAcceptable
+		}
+		return decoder(ctx, r)
+	}
+}
+
+// responseMediaType returns the media type negotiated for the response.
+func responseMediaType(ctx context.Context) string {
+	if mediaType, ok := ctx.Value(contextKeyResponseMediaType).(string); ok {
+		return mediaType
+	}
+	return MediaTypePayload
+}
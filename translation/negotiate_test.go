@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+func TestNegotiateMediaType(t *testing.T) {
+	tests := []struct {
+		accept     string
+		expected   string
+		expectedOK bool
+	}{
+		{accept: "", expected: MediaTypePayload, expectedOK: true},
+		{accept: "*/*", expected: MediaTypePayload, expectedOK: true},
+		{accept: "application/json", expected: MediaTypePayload, expectedOK: true},
+		{accept: "application/wrp+json", expected: MediaTypeWRPJSON, expectedOK: true},
+		{accept: "application/msgpack", expected: MediaTypeWRPMsgpack, expectedOK: true},
+		{accept: "application/wrp", expected: MediaTypeWRPMsgpack, expectedOK: true},
+		{accept: "text/html, application/msgpack, application/json", expected: MediaTypeWRPMsgpack, expectedOK: true},
+		{accept: "application/json;q=0.5, application/wrp+json", expected: MediaTypeWRPJSON, expectedOK: true},
+		{accept: "application/msgpack;q=0, */*;q=0.1", expected: MediaTypePayload, expectedOK: true},
+		{accept: "text/html"},
+		{accept: "application/json;q=0"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.accept, func(t *testing.T) {
+			mediaType, ok := negotiateMediaType(tc.accept)
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expected, mediaType)
+		})
+	}
+}
+
+func TestDecodeAcceptableRequest(t *testing.T) {
+	decoder := func(context.Context, *http.Request) (interface{}, error) {
+		return "decoded", nil
+	}
+
+	t.Run("strict", func(t *testing.T) {
+		assert := assert.New(t)
+		decode := decodeAcceptableRequest(true, decoder)
+
+		r := httptest.NewRequest(http.MethodGet, "http://localhost/test", nil)
+		r.Header.Set("Accept", "text/html")
+		_, err := decode(context.Background(), r)
+		var coded transaction.CodedError
+		if assert.ErrorAs(err, &coded) {
+			assert.Equal(http.StatusNotAcceptable, coded.StatusCode())
+		}
+
+		r.Header.Set("Accept", "application/wrp+json")
+		v, err := decode(context.Background(), r)
+		assert.NoError(err)
+		assert.Equal("decoded", v)
+	})
+
+	t.Run("lenient", func(t *testing.T) {
+		assert := assert.New(t)
+		decode := decodeAcceptableRequest(false, decoder)
+
+		r := httptest.NewRequest(http.MethodGet, "http://localhost/test", nil)
+		r.Header.Set("Accept", "text/plain")
+		v, err := decode(context.Background(), r)
+		assert.NoError(err)
+		assert.Equal("decoded", v)
+		assert.Equal(MediaTypePayload, responseMediaType(captureResponseMediaType(context.Background(), r)))
+	})
+}
+
+func TestEncodeResponseMediaTypes(t *testing.T) {
+	deviceResponse := &wrp.Message{
+		Type:            wrp.SimpleRequestResponseMessageType,
+		Source:          "mac:112233445566/config",
+		Destination:     "dns:tr1d1um.example.com",
+		TransactionUUID: "test-tid",
+		Payload:         []byte(`{"statusCode": 200}`),
+	}
+	body := wrp.MustEncode(deviceResponse, wrp.Msgpack)
+
+	tests := []struct {
+		accept       string
+		expectedType string
+		expectedBody []byte
+	}{
+		{
+			accept:       "application/json",
+			expectedType: MediaTypePayload,
+			expectedBody: deviceResponse.Payload,
+		},
+		{
+			accept:       "application/wrp+json",
+			expectedType: MediaTypeWRPJSON,
+			expectedBody: wrp.MustEncode(deviceResponse, wrp.JSON),
+		},
+		{
+			accept:       "application/msgpack",
+			expectedType: MediaTypeWRPMsgpack,
+			expectedBody: body,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.accept, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			r := httptest.NewRequest(http.MethodGet, "http://localhost/test", nil)
+			r.Header.Set("Accept", tc.accept)
+			ctx := captureResponseMediaType(ctxTID, r)
+
+			recorder := httptest.NewRecorder()
+			require.NoError(encodeResponse(ctx, recorder, &transaction.XmidtResponse{
+				Code: http.StatusOK,
+				Body: body,
+			}))
+
+			assert.Equal(http.StatusOK, recorder.Code)
+			assert.Equal(tc.expectedType, recorder.Header().Get("Content-Type"))
+			assert.Equal("Accept", recorder.Header().Get("Vary"))
+			assert.Equal(tc.expectedBody, recorder.Body.Bytes())
+		})
+	}
+}
@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/wrp-go/v3"
+	"github.com/xmidt-org/wrp-go/v3/wrphttp"
+)
+
+// Headers through which clients set fields of the WRP message sent to the device.
+const (
+	HeaderWRPQualityOfService = "X-Xmidt-Qos"
+	HeaderWRPSessionID        = wrphttp.SessionIdHeader
+	HeaderWRPHeaders          = wrphttp.HeadersHeader
+
+	// HeaderWRPMetadataPrefix is followed by the metadata key, e.g.
+	// "X-Xmidt-Metadata-Trust: 1000". A leading '/' of the allowed key,
+	// which headers can't carry, may be omitted.
+	HeaderWRPMetadataPrefix = wrphttp.MetadataHeader + "-"
+)
+
+// WRPPassthrough is the allow-list of WRP message fields clients may set
+// through headers. Headers setting fields that aren't allowed are ignored,
+// unless RejectNotAllowed is set.
+type WRPPassthrough struct {
+	// QualityOfService allows the X-Xmidt-Qos header.
+	QualityOfService bool
+
+	// SessionID allows the X-Xmidt-Session-Id header.
+	SessionID bool
+
+	// Metadata lists the metadata keys that may be set with
+	// X-Xmidt-Metadata-{key} headers.
+	Metadata []string
+
+	// Headers lists the WRP header names that may be set with
+	// X-Xmidt-Headers headers of the form "name: value".
+	Headers []string
+
+	// RejectNotAllowed rejects the requests setting fields that aren't
+	// allowed with a 400, instead of ignoring their headers.
+	RejectNotAllowed bool
+}
+
+// DecodeWRPPassthrough returns a decoder that sets the WRP fields passed
+// through by the client on the messages decoded by decoder.
+func DecodeWRPPassthrough(p WRPPassthrough, decoder kithttp.DecodeRequestFunc) kithttp.DecodeRequestFunc {
+	return func(ctx context.Context, r *http.Request) (interface{}, error) {
+		decodedRequest, err := decoder(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+
+		var messages []*wrp.Message
+		switch req := decodedRequest.(type) {
+		case *wrpRequest:
+			messages = []*wrp.Message{req.WRPMessage}
+		case *eventRequest:
+			messages = req.Messages
+		case *batchGetRequest:
+			messages = req.Pages
+		case *casRequest:
+			// the messages are wrapped as they are sent, check the headers now
+			if err = p.apply(r.Header, new(wrp.Message)); err != nil {
+				return nil, err
+			}
+			wrap := req.wrap
+			req.wrap = func(payload []byte) (*wrp.Message, error) {
+				msg, err := wrap(payload)
+				if err != nil {
+					return nil, err
+				}
+				return msg, p.apply(r.Header, msg)
+			}
+		}
+
+		for _, msg := range messages {
+			if err = p.apply(r.Header, msg); err != nil {
+				return nil, err
+			}
+		}
+
+		return decodedRequest, nil
+	}
+}
+
+// apply sets the fields passed through in h on msg.
+func (p WRPPassthrough) apply(h http.Header, msg *wrp.Message) error {
+	if v := h.Get(HeaderWRPQualityOfService); v != "" {
+		if p.QualityOfService {
+			qos, err := strconv.Atoi(v)
+			if err != nil || qos < 0 || qos > 99 {
+				return transaction.NewBadRequestError(fmt.Errorf("%s must be an integer between 0 and 99", HeaderWRPQualityOfService))
+			}
+			msg.QualityOfService = wrp.QOSValue(qos)
+		} else if p.RejectNotAllowed {
+			return notAllowedError(HeaderWRPQualityOfService)
+		}
+	}
+
+	if v := h.Get(HeaderWRPSessionID); v != "" {
+		if p.SessionID {
+			msg.SessionID = v
+		} else if p.RejectNotAllowed {
+			return notAllowedError(HeaderWRPSessionID)
+		}
+	}
+
+	for name, values := range h {
+		suffix, ok := strings.CutPrefix(name, HeaderWRPMetadataPrefix)
+		if !ok || len(values) == 0 {
+			continue
+		}
+		key, ok := p.metadataKey(suffix)
+		if !ok {
+			if p.RejectNotAllowed {
+				return notAllowedError(name)
+			}
+			continue
+		}
+		if msg.Metadata == nil {
+			msg.Metadata = make(map[string]string)
+		}
+		msg.Metadata[key] = values[0]
+	}
+
+	for _, v := range h.Values(HeaderWRPHeaders) {
+		name, value, ok := strings.Cut(v, ":")
+		name = strings.TrimSpace(name)
+		switch {
+		case ok && name != "" && containsFold(p.Headers, name):
+			msg.Headers = append(msg.Headers, name+": "+strings.TrimSpace(value))
+		case !p.RejectNotAllowed:
+			// ignored
+		case !ok || name == "":
+			return transaction.NewBadRequestError(fmt.Errorf("%s must be of the form 'name: value'", HeaderWRPHeaders))
+		default:
+			return notAllowedError(HeaderWRPHeaders + " " + name)
+		}
+	}
+
+	return nil
+}
+
+// metadataKey returns the allowed metadata key named by a header suffix.
+// Header names are case insensitive and can't carry a '/'.
+func (p WRPPassthrough) metadataKey(suffix string) (string, bool) {
+	for _, key := range p.Metadata {
+		if strings.EqualFold(strings.TrimPrefix(key, "/"), suffix) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+func notAllowedError(header string) error {
+	return transaction.NewBadRequestError(fmt.Errorf("%s is not allowed", header))
+}
+
+func containsFold(elements []string, s string) bool {
+	for _, e := range elements {
+		if strings.EqualFold(e, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+func TestDecodeWRPPassthrough(t *testing.T) {
+	passthrough := WRPPassthrough{
+		QualityOfService: true,
+		Metadata:         []string{"/trust"},
+		Headers:          []string{"x-priority"},
+	}
+
+	tests := []struct {
+		description string
+		headers     map[string][]string
+		reject      bool
+		expected    wrp.Message
+		expectedErr string
+	}{
+		{
+			description: "nothing passed through",
+		},
+		{
+			description: "allowed fields",
+			headers: map[string][]string{
+				HeaderWRPQualityOfService:         {"75"},
+				HeaderWRPMetadataPrefix + "trust": {"1000"},
+				HeaderWRPHeaders:                  {"X-Priority: high"},
+			},
+			expected: wrp.Message{
+				QualityOfService: 75,
+				Metadata:         map[string]string{"/trust": "1000"},
+				Headers:          []string{"X-Priority: high"},
+			},
+		},
+		{
+			description: "invalid qos",
+			headers:     map[string][]string{HeaderWRPQualityOfService: {"100"}},
+			expectedErr: "X-Xmidt-Qos must be an integer between 0 and 99",
+		},
+		{
+			description: "fields not allowed are ignored",
+			headers: map[string][]string{
+				HeaderWRPSessionID:                    {"abc"},
+				HeaderWRPMetadataPrefix + "boot-time": {"1"},
+				HeaderWRPHeaders:                      {"x-other: 1", "x-priority"},
+			},
+		},
+		{
+			description: "session id not allowed",
+			headers:     map[string][]string{HeaderWRPSessionID: {"abc"}},
+			reject:      true,
+			expectedErr: "X-Xmidt-Session-Id is not allowed",
+		},
+		{
+			description: "metadata not allowed",
+			headers:     map[string][]string{HeaderWRPMetadataPrefix + "boot-time": {"1"}},
+			reject:      true,
+			expectedErr: "X-Xmidt-Metadata-Boot-Time is not allowed",
+		},
+		{
+			description: "header not allowed",
+			headers:     map[string][]string{HeaderWRPHeaders: {"x-other: 1"}},
+			reject:      true,
+			expectedErr: "X-Xmidt-Headers x-other is not allowed",
+		},
+		{
+			description: "malformed header",
+			headers:     map[string][]string{HeaderWRPHeaders: {"x-priority"}},
+			reject:      true,
+			expectedErr: "X-Xmidt-Headers must be of the form 'name: value'",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			passthrough := passthrough
+			passthrough.RejectNotAllowed = tc.reject
+			decode := DecodeWRPPassthrough(passthrough, func(context.Context, *http.Request) (interface{}, error) {
+				return &wrpRequest{WRPMessage: new(wrp.Message)}, nil
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "http://localhost/api/v2/device/mac:112233445566/config", nil)
+			for name, values := range tc.headers {
+				for _, v := range values {
+					r.Header.Add(name, v)
+				}
+			}
+
+			decoded, err := decode(context.Background(), r)
+			if tc.expectedErr != "" {
+				assert.EqualError(err, tc.expectedErr)
+				return
+			}
+
+			if assert.NoError(err) {
+				assert.Equal(&tc.expected, decoded.(*wrpRequest).WRPMessage)
+			}
+		})
+	}
+}
+
+func TestDecodeWRPPassthroughCAS(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	decode := DecodeWRPPassthrough(WRPPassthrough{QualityOfService: true, RejectNotAllowed: true}, func(context.Context, *http.Request) (interface{}, error) {
+		return &casRequest{wrap: func(payload []byte) (*wrp.Message, error) {
+			return &wrp.Message{Payload: payload}, nil
+		}}, nil
+	})
+
+	r := httptest.NewRequest(http.MethodPatch, "http://localhost/api/v2/device/mac:112233445566/config", nil)
+	r.Header.Set(HeaderWRPQualityOfService, "75")
+	decoded, err := decode(context.Background(), r)
+	require.NoError(err)
+
+	msg, err := decoded.(*casRequest).wrap([]byte("{}"))
+	require.NoError(err)
+	assert.Equal(wrp.QOSValue(75), msg.QualityOfService)
+
+	r.Header.Set(HeaderWRPSessionID, "abc")
+	_, err = decode(context.Background(), r)
+	assert.EqualError(err, "X-Xmidt-Session-Id is not allowed")
+}
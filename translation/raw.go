@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/xmidt-org/candlelight"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+// rawFormatParam is the query parameter telling how the body of a raw WRP
+// request is to be read. With format=wrp, the body is a WRP message in msgpack
+// or JSON, according to its Content-Type, of which only the type, payload and
+// content type are kept. Otherwise, the body is the payload of the WRP
+// message.
+const (
+	rawFormatParam = "format"
+	rawFormatWRP   = "wrp"
+)
+
+// Raw WRP request errors
+var (
+	ErrInvalidWRPMessage  = transaction.NewBadRequestError(errors.New("invalid WRP message"))
+	ErrUnsupportedWRPType = transaction.NewBadRequestError(errors.New("only SimpleRequestResponse WRP messages are supported"))
+)
+
+// decodeRawRequest decodes a request carrying a WRP payload, or a full WRP
+// message, for a device service. The destination, transaction ID, partner IDs
+// and trace headers are set by tr1d1um as they are for WDMP requests, and the
+// other fields clients may set are only set through WRPPassthrough headers.
+func decodeRawRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	deviceID, err := wrp.ParseDeviceID(mux.Vars(r)["deviceid"])
+	if err != nil {
+		return nil, transaction.NewBadRequestError(err)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, ErrInvalidPayload
+	}
+
+	wrpMsg := new(wrp.Message)
+	contentType := r.Header.Get(contentTypeHeaderKey)
+
+	if r.URL.Query().Get(rawFormatParam) == rawFormatWRP {
+		format, err := wrp.FormatFromContentType(contentType, wrp.Msgpack)
+		if err != nil {
+			return nil, transaction.NewBadRequestError(err)
+		}
+		decoded := new(wrp.Message)
+		if err = wrp.NewDecoderBytes(body, format).Decode(decoded); err != nil {
+			return nil, ErrInvalidWRPMessage
+		}
+
+		switch decoded.Type {
+		case wrp.SimpleRequestResponseMessageType, wrp.Invalid0MessageType:
+		default:
+			return nil, ErrUnsupportedWRPType
+		}
+		wrpMsg.Type = wrp.SimpleRequestResponseMessageType
+		wrpMsg.Payload, wrpMsg.ContentType = decoded.Payload, decoded.ContentType
+	} else {
+		if contentType == "" {
+			contentType = wrp.MimeTypeOctetStream
+		}
+		wrpMsg.Type = wrp.SimpleRequestResponseMessageType
+		wrpMsg.Payload, wrpMsg.ContentType = body, contentType
+	}
+
+	wrpMsg.Destination = fmt.Sprintf("%s/%s", string(deviceID), mux.Vars(r)["service"])
+	wrpMsg.TransactionUUID = getTID(ctx)
+	wrpMsg.PartnerIDs = getPartnerIDsDecodeRequest(ctx, r)
+	wrpMsg.Headers = append(wrpMsg.Headers, getTraceHeaders(r.Header)...)
+
+	return &wrpRequest{
+		WRPMessage:      wrpMsg,
+		AuthHeaderValue: r.Header.Get(authHeaderKey),
+	}, nil
+}
+
+// encodeRawResponse writes the payload and content type of the device
+// response as they are.
+func encodeRawResponse(ctx context.Context, w http.ResponseWriter, response interface{}) (err error) {
+	var resp = response.(*transaction.XmidtResponse)
+
+	transaction.ForwardHeadersByPrefix("", resp.ForwardedHeaders, w.Header())
+	w.Header().Set(candlelight.HeaderWPATIDKeyName, getTID(ctx))
+
+	if resp.Code != http.StatusOK || len(resp.Body) == 0 {
+		w.WriteHeader(resp.Code)
+		_, err = w.Write(resp.Body)
+		return
+	}
+
+	wrpModel := new(wrp.Message)
+	if err = wrp.NewDecoderBytes(resp.Body, wrp.Msgpack).Decode(wrpModel); err != nil {
+		return
+	}
+
+	contentType := wrpModel.ContentType
+	if contentType == "" {
+		contentType = wrp.MimeTypeOctetStream
+	}
+	w.Header().Set(contentTypeHeaderKey, contentType)
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(wrpModel.Payload)
+	return
+}
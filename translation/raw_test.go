@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+func TestDecodeRawRequest(t *testing.T) {
+	tests := []struct {
+		description string
+		url         string
+		contentType string
+		body        []byte
+		expected    *wrp.Message
+		expectedErr error
+	}{
+		{
+			description: "raw payload",
+			url:         "http://localhost/api/v3/wrp/device/mac:112233445566/iot",
+			contentType: "text/plain",
+			body:        []byte("hello"),
+			expected: &wrp.Message{
+				Type:        wrp.SimpleRequestResponseMessageType,
+				Payload:     []byte("hello"),
+				ContentType: "text/plain",
+			},
+		},
+		{
+			description: "raw payload without content type",
+			url:         "http://localhost/api/v3/wrp/device/mac:112233445566/iot",
+			body:        []byte{0x01},
+			expected: &wrp.Message{
+				Type:        wrp.SimpleRequestResponseMessageType,
+				Payload:     []byte{0x01},
+				ContentType: wrp.MimeTypeOctetStream,
+			},
+		},
+		{
+			description: "msgpack WRP message keeps its payload only",
+			url:         "http://localhost/api/v3/wrp/device/mac:112233445566/iot?format=wrp",
+			contentType: wrp.MimeTypeMsgpack,
+			body: wrp.MustEncode(&wrp.Message{
+				Type:             wrp.SimpleRequestResponseMessageType,
+				Destination:      "mac:ffffffffffff/other",
+				TransactionUUID:  "client-tid",
+				Payload:          []byte("hello"),
+				ContentType:      "text/plain",
+				SessionID:        "session",
+				Metadata:         map[string]string{"/trust": "1000"},
+				Headers:          []string{"x-priority: high"},
+				QualityOfService: 99,
+			}, wrp.Msgpack),
+			expected: &wrp.Message{
+				Type:        wrp.SimpleRequestResponseMessageType,
+				Payload:     []byte("hello"),
+				ContentType: "text/plain",
+			},
+		},
+		{
+			description: "JSON WRP message without type",
+			url:         "http://localhost/api/v3/wrp/device/mac:112233445566/iot?format=wrp",
+			contentType: wrp.MimeTypeJson,
+			body:        []byte(`{"payload": "aGVsbG8=", "content_type": "text/plain"}`),
+			expected: &wrp.Message{
+				Type:        wrp.SimpleRequestResponseMessageType,
+				Payload:     []byte("hello"),
+				ContentType: "text/plain",
+			},
+		},
+		{
+			description: "unsupported WRP message type",
+			url:         "http://localhost/api/v3/wrp/device/mac:112233445566/iot?format=wrp",
+			contentType: wrp.MimeTypeMsgpack,
+			body:        wrp.MustEncode(&wrp.Message{Type: wrp.SimpleEventMessageType}, wrp.Msgpack),
+			expectedErr: ErrUnsupportedWRPType,
+		},
+		{
+			description: "invalid WRP message",
+			url:         "http://localhost/api/v3/wrp/device/mac:112233445566/iot?format=wrp",
+			contentType: wrp.MimeTypeJson,
+			body:        []byte("{"),
+			expectedErr: ErrInvalidWRPMessage,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			r := httptest.NewRequest(http.MethodPost, tc.url, bytes.NewReader(tc.body))
+			r = mux.SetURLVars(r, map[string]string{"deviceid": "mac:112233445566", "service": "iot"})
+			r.Header.Set(authHeaderKey, "Basic xyz==")
+			r.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+			if tc.contentType != "" {
+				r.Header.Set(contentTypeHeaderKey, tc.contentType)
+			}
+
+			decoded, err := decodeRawRequest(ctxTID, r)
+			if tc.expectedErr != nil {
+				assert.Equal(tc.expectedErr, err)
+				return
+			}
+			require.NoError(err)
+
+			expected := *tc.expected
+			expected.Destination = "mac:112233445566/iot"
+			expected.TransactionUUID = "test-tid"
+			expected.Headers = []string{"traceparent: 00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", "tracestate: "}
+
+			req := decoded.(*wrpRequest)
+			assert.Equal("Basic xyz==", req.AuthHeaderValue)
+			assert.Equal(&expected, req.WRPMessage)
+		})
+	}
+
+	t.Run("invalid device ID", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "http://localhost/api/v3/wrp/device/bad/iot", nil)
+		r = mux.SetURLVars(r, map[string]string{"deviceid": "bad", "service": "iot"})
+
+		_, err := decodeRawRequest(ctxTID, r)
+		var coded transaction.CodedError
+		require.ErrorAs(t, err, &coded)
+		assert.Equal(t, http.StatusBadRequest, coded.StatusCode())
+	})
+}
+
+func TestEncodeRawResponse(t *testing.T) {
+	t.Run("device response", func(t *testing.T) {
+		assert := assert.New(t)
+		w := httptest.NewRecorder()
+
+		err := encodeRawResponse(ctxTID, w, &transaction.XmidtResponse{
+			Code: http.StatusOK,
+			Body: wrp.MustEncode(&wrp.Message{
+				Type:        wrp.SimpleRequestResponseMessageType,
+				Payload:     []byte("hi"),
+				ContentType: "text/plain",
+			}, wrp.Msgpack),
+			ForwardedHeaders: http.Header{contentTypeHeaderKey: []string{wrp.MimeTypeMsgpack}},
+		})
+
+		assert.NoError(err)
+		assert.Equal(http.StatusOK, w.Code)
+		assert.Equal("text/plain", w.Header().Get(contentTypeHeaderKey))
+		assert.Equal("test-tid", w.Header().Get("X-WebPA-Transaction-Id"))
+		assert.Equal("hi", w.Body.String())
+	})
+
+	t.Run("XMiDT error", func(t *testing.T) {
+		assert := assert.New(t)
+		w := httptest.NewRecorder()
+
+		err := encodeRawResponse(ctxTID, w, &transaction.XmidtResponse{
+			Code: http.StatusNotFound,
+			Body: []byte("device not found"),
+		})
+
+		assert.NoError(err)
+		assert.Equal(http.StatusNotFound, w.Code)
+		assert.Equal("device not found", w.Body.String())
+	})
+}
@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/xmidt-org/tr1d1um/transaction"
+)
+
+// WDMP parameter data types
+const (
+	DataTypeString int8 = iota
+	DataTypeInt
+	DataTypeUInt
+	DataTypeBoolean
+	DataTypeDateTime
+	DataTypeBase64
+	DataTypeLong
+	DataTypeULong
+	DataTypeFloat
+	DataTypeDouble
+	DataTypeByte
+)
+
+var errInvalidColumnValue = errors.New(`must be a string, a number, a boolean or a {"value": ..., "dataType": ...} object`)
+
+// hintedValue is a column value with a dataType hint.
+type hintedValue struct {
+	Value    json.RawMessage `json:"value"`
+	DataType *int8           `json:"dataType"`
+}
+
+// normalizeRow returns the WDMP wire form of a row, whose columns values are
+// strings. Column values may be given as strings, numbers, booleans or objects
+// with a value and a dataType hint.
+func normalizeRow(row map[string]json.RawMessage) (map[string]string, error) {
+	normalized := make(map[string]string, len(row))
+	for _, column := range slices.Sorted(maps.Keys(row)) {
+		value, err := normalizeColumnValue(row[column])
+		if err != nil {
+			return nil, transaction.NewBadRequestError(fmt.Errorf("invalid value of column '%s': %w", column, err))
+		}
+		normalized[column] = value
+	}
+	return normalized, nil
+}
+
+func normalizeColumnValue(raw json.RawMessage) (string, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+		var hinted hintedValue
+		if err := strictUnmarshal(raw, &hinted); err != nil || hinted.Value == nil || hinted.DataType == nil {
+			return "", errInvalidColumnValue
+		}
+
+		value, err := decodeScalar(hinted.Value)
+		if err != nil {
+			return "", err
+		}
+		return convertColumnValue(value, *hinted.DataType)
+	}
+
+	value, err := decodeScalar(raw)
+	if err != nil {
+		return "", err
+	}
+	return formatScalar(value), nil
+}
+
+// decodeScalar decodes a string, number or boolean JSON value.
+func decodeScalar(raw json.RawMessage) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, errInvalidColumnValue
+	}
+
+	switch v.(type) {
+	case string, json.Number, bool:
+		return v, nil
+	default:
+		return nil, errInvalidColumnValue
+	}
+}
+
+func formatScalar(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case json.Number:
+		return x.String()
+	case bool:
+		return strconv.FormatBool(x)
+	default:
+		return ""
+	}
+}
+
+// convertColumnValue checks a value against its dataType and returns it in
+// the form the device expects.
+func convertColumnValue(v interface{}, dataType int8) (string, error) {
+	text := formatScalar(v)
+
+	var err error
+	switch dataType {
+	case DataTypeString, DataTypeDateTime:
+		return text, nil
+	case DataTypeInt, DataTypeLong:
+		bits := 64
+		if dataType == DataTypeInt {
+			bits = 32
+		}
+		var n int64
+		if n, err = strconv.ParseInt(text, 10, bits); err == nil {
+			return strconv.FormatInt(n, 10), nil
+		}
+	case DataTypeUInt, DataTypeULong, DataTypeByte:
+		bits := map[int8]int{DataTypeUInt: 32, DataTypeULong: 64, DataTypeByte: 8}[dataType]
+		var n uint64
+		if n, err = strconv.ParseUint(text, 10, bits); err == nil {
+			return strconv.FormatUint(n, 10), nil
+		}
+	case DataTypeBoolean:
+		var b bool
+		if b, err = strconv.ParseBool(text); err == nil {
+			return strconv.FormatBool(b), nil
+		}
+	case DataTypeBase64:
+		if _, err = base64.StdEncoding.DecodeString(text); err == nil {
+			return text, nil
+		}
+	case DataTypeFloat, DataTypeDouble:
+		bits := 64
+		if dataType == DataTypeFloat {
+			bits = 32
+		}
+		if _, err = strconv.ParseFloat(text, bits); err == nil {
+			return text, nil
+		}
+	default:
+		return "", fmt.Errorf("unknown dataType %d", dataType)
+	}
+
+	return "", fmt.Errorf("'%s' is not a valid value of dataType %d", text, dataType)
+}
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeColumnValue(t *testing.T) {
+	tests := []struct {
+		description   string
+		value         string
+		expectedValue string
+		expectedErr   string
+	}{
+		{description: "string", value: `"home"`, expectedValue: "home"},
+		{description: "integer", value: `42`, expectedValue: "42"},
+		{description: "float", value: `1.50`, expectedValue: "1.50"},
+		{description: "boolean", value: `true`, expectedValue: "true"},
+		{description: "hinted boolean", value: `{"value": "1", "dataType": 3}`, expectedValue: "true"},
+		{description: "hinted int", value: `{"value": "-07", "dataType": 1}`, expectedValue: "-7"},
+		{description: "hinted unsigned int from number", value: `{"value": 8, "dataType": 2}`, expectedValue: "8"},
+		{description: "hinted string from number", value: `{"value": 8, "dataType": 0}`, expectedValue: "8"},
+		{description: "hinted base64", value: `{"value": "aGVsbG8=", "dataType": 5}`, expectedValue: "aGVsbG8="},
+		{description: "hinted double", value: `{"value": 2.5, "dataType": 9}`, expectedValue: "2.5"},
+		{
+			description: "hinted byte out of range",
+			value:       `{"value": 256, "dataType": 10}`,
+			expectedErr: "'256' is not a valid value of dataType 10",
+		},
+		{
+			description: "hinted int out of range",
+			value:       `{"value": 4294967296, "dataType": 1}`,
+			expectedErr: "'4294967296' is not a valid value of dataType 1",
+		},
+		{
+			description: "hinted invalid boolean",
+			value:       `{"value": "yes", "dataType": 3}`,
+			expectedErr: "'yes' is not a valid value of dataType 3",
+		},
+		{
+			description: "unknown dataType",
+			value:       `{"value": "v", "dataType": 11}`,
+			expectedErr: "unknown dataType 11",
+		},
+		{
+			description: "hint without dataType",
+			value:       `{"value": "v"}`,
+			expectedErr: errInvalidColumnValue.Error(),
+		},
+		{
+			description: "hint with unknown field",
+			value:       `{"value": "v", "dataType": 0, "type": "string"}`,
+			expectedErr: errInvalidColumnValue.Error(),
+		},
+		{
+			description: "nested hint",
+			value:       `{"value": {"value": "v", "dataType": 0}, "dataType": 0}`,
+			expectedErr: errInvalidColumnValue.Error(),
+		},
+		{description: "null", value: `null`, expectedErr: errInvalidColumnValue.Error()},
+		{description: "array", value: `["a"]`, expectedErr: errInvalidColumnValue.Error()},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			value, err := normalizeColumnValue(json.RawMessage(tc.value))
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedValue, value)
+		})
+	}
+}
+
+func TestTypedRowPayloads(t *testing.T) {
+	t.Run("add row", func(t *testing.T) {
+		p, err := requestAddPayload(map[string]string{"parameter": "Device.WiFi.AccessPoint."},
+			bytes.NewBufferString(`{"SSID": "home", "Enable": true, "MaxAssociatedDevices": {"value": 32, "dataType": 2}}`))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"command": "ADD_ROW", "table": "Device.WiFi.AccessPoint.", "row": {"SSID": "home", "Enable": "true", "MaxAssociatedDevices": "32"}}`, string(p))
+	})
+
+	t.Run("replace rows", func(t *testing.T) {
+		p, err := requestReplacePayload(map[string]string{"parameter": "Device.WiFi.AccessPoint."},
+			bytes.NewBufferString(`{"0": {"SSID": "home", "Enable": {"value": "false", "dataType": 3}}, "1": {"SSID": "guest", "Channel": 6}}`))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"command": "REPLACE_ROWS", "table": "Device.WiFi.AccessPoint.", "rows": {"0": {"SSID": "home", "Enable": "false"}, "1": {"SSID": "guest", "Channel": "6"}}}`, string(p))
+	})
+
+	t.Run("invalid column value", func(t *testing.T) {
+		_, err := requestReplacePayload(map[string]string{"parameter": "Device.WiFi.AccessPoint."},
+			bytes.NewBufferString(`{"0": {"Channel": {"value": "six", "dataType": 2}}}`))
+		assert.EqualError(t, err, "invalid value of column 'Channel': 'six' is not a valid value of dataType 2")
+	})
+}
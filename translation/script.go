@@ -0,0 +1,456 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/gorilla/mux"
+	"github.com/xmidt-org/candlelight"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+// DefaultMaxScriptSteps is the default maximum number of steps of a script.
+const DefaultMaxScriptSteps = 20
+
+// Script step statuses
+const (
+	StepApplied        = "applied"
+	StepFailed         = "failed"
+	StepSkipped        = "skipped"
+	StepRolledBack     = "rolledBack"
+	StepRollbackFailed = "rollbackFailed"
+)
+
+// Script request errors
+var (
+	ErrMissingScriptSteps = transaction.NewBadRequestError(errors.New("script must have at least one step"))
+	ErrTooManyScriptSteps = transaction.NewBadRequestError(errors.New("too many script steps"))
+	ErrInvalidScript      = transaction.NewBadRequestError(errors.New("script is invalid"))
+	ErrInvalidCommand     = transaction.NewBadRequestError(errors.New("command is invalid"))
+)
+
+// ScriptConfig configures the endpoint running scripts of WDMP commands
+// against a device.
+type ScriptConfig struct {
+	// MaxSteps is the maximum number of steps of a script.
+	// (Optional) Defaults to DefaultMaxScriptSteps.
+	MaxSteps int
+}
+
+// scriptStep is a step of a script request. Steps use the SET, ADD_ROW,
+// REPLACE_ROWS and DELETE_ROW forms of the WDMP commands, e.g.
+// {"command": "DELETE_ROW", "row": "Device.WiFi.AccessPoint.1."}.
+type scriptStep struct {
+	Command    string          `json:"command"`
+	Parameters json.RawMessage `json:"parameters,omitempty"`
+	Table      string          `json:"table,omitempty"`
+	Row        json.RawMessage `json:"row,omitempty"`
+	Rows       json.RawMessage `json:"rows,omitempty"`
+}
+
+type scriptRequestBody struct {
+	// Rollback asks for the steps already applied to be undone when a step fails.
+	Rollback bool         `json:"rollback"`
+	Steps    []scriptStep `json:"steps"`
+}
+
+// scriptCommand is a step translated into its WDMP payload.
+type scriptCommand struct {
+	Command string
+	Payload []byte
+
+	// Names lists the names whose values are captured before the script
+	// runs, to roll the command back.
+	Names []string
+
+	// Table is the table of ADD_ROW and REPLACE_ROWS commands.
+	Table string
+
+	// Row is the row of DELETE_ROW commands.
+	Row string
+}
+
+type scriptRequest struct {
+	Commands        []scriptCommand
+	Rollback        bool
+	AuthHeaderValue string
+
+	// wrap wraps a WDMP payload into a WRP message to the device.
+	wrap func([]byte) (*wrp.Message, error)
+}
+
+// CommandResult is the outcome of a WDMP command.
+type CommandResult struct {
+	StatusCode int    `json:"statusCode"`
+	Message    string `json:"message,omitempty"`
+}
+
+type scriptStepResult struct {
+	Command string `json:"command"`
+	Status  string `json:"status"`
+	CommandResult
+	Rollback *CommandResult `json:"rollback,omitempty"`
+}
+
+type scriptResponse struct {
+	statusCode int
+
+	// Capture is the outcome of the GET capturing current values, when it failed.
+	Capture *CommandResult     `json:"capture,omitempty"`
+	Steps   []scriptStepResult `json:"steps"`
+}
+
+// capturedParam is a parameter returned by a GET.
+type capturedParam struct {
+	Name     string          `json:"name"`
+	Value    json.RawMessage `json:"value"`
+	DataType int8            `json:"dataType"`
+}
+
+// translateStep checks a script step, including its rows against the table
+// schemas, and translates it into a WDMP command.
+func translateStep(step scriptStep, schemas TableSchemas) (c scriptCommand, err error) {
+	c.Command = step.Command
+
+	switch step.Command {
+	case CommandSet:
+		if len(step.Parameters) == 0 {
+			return c, ErrInvalidSetWDMP
+		}
+		var wdmp *setWDMP
+		wdmp, err = loadWDMP(append(append([]byte(`{"parameters":`), step.Parameters...), '}'), "", "", "")
+		if err == nil && wdmp.Command != CommandSet {
+			err = ErrInvalidSetWDMP
+		}
+		if err == nil {
+			c.Names = getParamNames(wdmp.Parameters)
+			c.Payload, err = json.Marshal(wdmp)
+		}
+	case CommandAddRow:
+		c.Table = step.Table
+		c.Payload, err = requestAddPayload(map[string]string{"parameter": step.Table}, bytes.NewReader(step.Row))
+	case CommandReplaceRows:
+		c.Table, c.Names = step.Table, []string{step.Table}
+		c.Payload, err = requestReplacePayload(map[string]string{"parameter": step.Table}, bytes.NewReader(step.Rows))
+	case CommandDeleteRow:
+		if err = json.Unmarshal(step.Row, &c.Row); err != nil {
+			return c, ErrInvalidRow
+		}
+		c.Names = []string{c.Row}
+		c.Payload, err = requestDeletePayload(map[string]string{"parameter": c.Row})
+	default:
+		err = transaction.NewBadRequestError(fmt.Errorf("unsupported command '%s', expected one of %s, %s, %s or %s",
+			step.Command, CommandSet, CommandAddRow, CommandReplaceRows, CommandDeleteRow))
+	}
+	if err == nil {
+		err = schemas.validatePayload(c.Payload)
+	}
+
+	return c, err
+}
+
+// CommandPayload checks a SET, ADD_ROW, REPLACE_ROWS or DELETE_ROW command,
+// given in the form of a script step, and returns its WDMP payload. The rows
+// of ADD_ROW and REPLACE_ROWS commands are checked against schemas.
+func CommandPayload(command []byte, schemas TableSchemas) ([]byte, error) {
+	var step scriptStep
+	if err := strictUnmarshal(command, &step); err != nil {
+		return nil, ErrInvalidCommand
+	}
+
+	c, err := translateStep(step, schemas)
+	if err != nil {
+		return nil, err
+	}
+	return c.Payload, nil
+}
+
+// decodeScriptRequest returns a decoder for scripts of WDMP commands, whose
+// rows are checked against schemas.
+func decodeScriptRequest(c ScriptConfig, schemas TableSchemas) func(context.Context, *http.Request) (interface{}, error) {
+	maxSteps := c.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = DefaultMaxScriptSteps
+	}
+
+	return func(ctx context.Context, r *http.Request) (interface{}, error) {
+		data, _ := getRequestBody(ctx)
+
+		var body scriptRequestBody
+		if err := strictUnmarshal(data, &body); err != nil {
+			return nil, ErrInvalidScript
+		}
+		if len(body.Steps) == 0 {
+			return nil, ErrMissingScriptSteps
+		}
+		if len(body.Steps) > maxSteps {
+			return nil, ErrTooManyScriptSteps
+		}
+
+		req := &scriptRequest{
+			Commands:        make([]scriptCommand, len(body.Steps)),
+			Rollback:        body.Rollback,
+			AuthHeaderValue: r.Header.Get(authHeaderKey),
+		}
+		for i, step := range body.Steps {
+			command, err := translateStep(step, schemas)
+			if err != nil {
+				return nil, transaction.NewBadRequestError(fmt.Errorf("step %d: %w", i+1, err))
+			}
+			req.Commands[i] = command
+		}
+
+		var (
+			tid          = getTID(ctx)
+			partnerIDs   = getPartnerIDsDecodeRequest(ctx, r)
+			traceHeaders = getTraceHeaders(r.Header)
+			vars         = mux.Vars(r)
+		)
+		if _, err := wrp.ParseDeviceID(vars["deviceid"]); err != nil {
+			return nil, transaction.NewBadRequestError(err)
+		}
+		req.wrap = func(payload []byte) (*wrp.Message, error) {
+			return wrap(payload, tid, vars, partnerIDs, slices.Clone(traceHeaders))
+		}
+
+		return req, nil
+	}
+}
+
+// makeScriptEndpoint runs scripts: it captures the values the rollback of the
+// script needs, applies the steps in order until one fails and, if asked to,
+// rolls back the steps already applied in reverse order.
+func makeScriptEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*scriptRequest)
+		send := func(payload []byte) (CommandResult, []byte) {
+			msg, err := req.wrap(payload)
+			if err != nil {
+				return CommandResult{StatusCode: http.StatusInternalServerError, Message: transaction.ErrTr1d1umInternal.Error()}, nil
+			}
+			return DeviceResult(s.SendWRP(ctx, msg, req.AuthHeaderValue))
+		}
+
+		resp := &scriptResponse{
+			statusCode: http.StatusOK,
+			Steps:      make([]scriptStepResult, len(req.Commands)),
+		}
+		for i, c := range req.Commands {
+			resp.Steps[i] = scriptStepResult{Command: c.Command, Status: StepSkipped}
+		}
+
+		captured, result := captureValues(req.Commands, send)
+		if result != nil {
+			resp.statusCode, resp.Capture = result.StatusCode, result
+			return resp, nil
+		}
+
+		devicePayloads := make([][]byte, len(req.Commands))
+		failed := -1
+		for i, c := range req.Commands {
+			result, payload := send(c.Payload)
+			resp.Steps[i].CommandResult = result
+			if !succeeded(result) {
+				resp.Steps[i].Status, resp.statusCode = StepFailed, result.StatusCode
+				failed = i
+				break
+			}
+			resp.Steps[i].Status, devicePayloads[i] = StepApplied, payload
+		}
+
+		if failed < 0 || !req.Rollback {
+			return resp, nil
+		}
+
+		for i := failed - 1; i >= 0; i-- {
+			step := &resp.Steps[i]
+			payload, err := req.Commands[i].rollbackPayload(captured, devicePayloads[i])
+			if err != nil {
+				step.Status, step.Rollback = StepRollbackFailed, &CommandResult{StatusCode: http.StatusInternalServerError, Message: err.Error()}
+				continue
+			}
+
+			result, _ := send(payload)
+			step.Status, step.Rollback = StepRolledBack, &result
+			if !succeeded(result) {
+				step.Status = StepRollbackFailed
+			}
+		}
+
+		return resp, nil
+	}
+}
+
+func succeeded(r CommandResult) bool {
+	return r.StatusCode >= http.StatusOK && r.StatusCode < http.StatusMultipleChoices
+}
+
+// DeviceResult returns the outcome of a WDMP command from the XMiDT response,
+// along with the WDMP payload of the device response.
+func DeviceResult(resp *transaction.XmidtResponse, err error) (CommandResult, []byte) {
+	if err != nil {
+		var ce transaction.CodedError
+		if errors.As(err, &ce) {
+			return CommandResult{StatusCode: ce.StatusCode(), Message: err.Error()}, nil
+		}
+		return CommandResult{StatusCode: http.StatusInternalServerError, Message: transaction.ErrTr1d1umInternal.Error()}, nil
+	}
+
+	if resp.Code != http.StatusOK {
+		return CommandResult{StatusCode: resp.Code, Message: string(resp.Body)}, nil
+	}
+
+	var (
+		deviceResponse wrp.Message
+		result         CommandResult
+	)
+	if err = wrp.NewDecoderBytes(resp.Body, wrp.Msgpack).Decode(&deviceResponse); err == nil {
+		err = json.Unmarshal(deviceResponse.Payload, &result)
+	}
+	if err != nil || result.StatusCode == 0 {
+		return CommandResult{StatusCode: http.StatusBadGateway, Message: "invalid device response"}, nil
+	}
+
+	return result, deviceResponse.Payload
+}
+
+// captureValues GETs the current values of the names the commands may need
+// to be rolled back, or returns the outcome of the GET if it failed.
+func captureValues(commands []scriptCommand, send func([]byte) (CommandResult, []byte)) (map[string]capturedParam, *CommandResult) {
+	var names []string
+	for _, c := range commands {
+		for _, name := range c.Names {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	captured := make(map[string]capturedParam)
+	if len(names) == 0 {
+		return captured, nil
+	}
+
+	payload, err := json.Marshal(&getWDMP{Command: CommandGet, Names: names})
+	if err != nil {
+		return nil, &CommandResult{StatusCode: http.StatusInternalServerError, Message: transaction.ErrTr1d1umInternal.Error()}
+	}
+
+	result, devicePayload := send(payload)
+	if !succeeded(result) {
+		return nil, &result
+	}
+
+	var getResponse struct {
+		Parameters []capturedParam `json:"parameters"`
+	}
+	if err = json.Unmarshal(devicePayload, &getResponse); err != nil {
+		return nil, &CommandResult{StatusCode: http.StatusBadGateway, Message: "invalid device response"}
+	}
+	flattenParams(getResponse.Parameters, captured)
+
+	return captured, nil
+}
+
+// flattenParams puts the leaf parameters of a GET response in captured.
+// Wildcard names have the parameters under them as value.
+func flattenParams(params []capturedParam, captured map[string]capturedParam) {
+	for _, p := range params {
+		var children []capturedParam
+		if err := json.Unmarshal(p.Value, &children); err == nil {
+			flattenParams(children, captured)
+			continue
+		}
+		captured[p.Name] = p
+	}
+}
+
+// columns returns the columns of a row, from the captured parameters.
+func columns(captured map[string]capturedParam, row string) map[string]string {
+	values := make(map[string]string)
+	for name, p := range captured {
+		column, ok := strings.CutPrefix(name, row)
+		if !ok || strings.Contains(column, ".") {
+			continue
+		}
+		if value, err := normalizeColumnValue(p.Value); err == nil {
+			values[column] = value
+		}
+	}
+	return values
+}
+
+// rollbackPayload returns the WDMP payload undoing the command.
+func (c scriptCommand) rollbackPayload(captured map[string]capturedParam, devicePayload []byte) ([]byte, error) {
+	switch c.Command {
+	case CommandSet:
+		wdmp := &setWDMP{Command: CommandSet}
+		for _, name := range c.Names {
+			p, ok := captured[name]
+			if !ok {
+				return nil, fmt.Errorf("no value of '%s' was captured", name)
+			}
+			wdmp.Parameters = append(wdmp.Parameters, setParam{Name: &name, DataType: &p.DataType, Value: p.Value})
+		}
+		return json.Marshal(wdmp)
+
+	case CommandAddRow:
+		var added struct {
+			Row string `json:"row"`
+		}
+		if err := json.Unmarshal(devicePayload, &added); err != nil || validateRowPath(added.Row) != nil {
+			return nil, errors.New("the device did not report the added row")
+		}
+		return json.Marshal(&deleteRowDMP{Command: CommandDeleteRow, Row: added.Row})
+
+	case CommandReplaceRows:
+		rows := make(indexRow)
+		for name := range captured {
+			rest, ok := strings.CutPrefix(name, c.Table)
+			if !ok {
+				continue
+			}
+			index, _, ok := strings.Cut(rest, ".")
+			if _, err := strconv.Atoi(index); !ok || err != nil {
+				continue
+			}
+			if _, ok := rows[index]; !ok {
+				rows[index] = columns(captured, c.Table+index+".")
+			}
+		}
+		return json.Marshal(&replaceRowsWDMP{Command: CommandReplaceRows, Table: c.Table, Rows: rows})
+
+	case CommandDeleteRow:
+		row := columns(captured, c.Row)
+		if len(row) == 0 {
+			return nil, fmt.Errorf("no columns of '%s' were captured", c.Row)
+		}
+		instance := strings.LastIndex(strings.TrimSuffix(c.Row, "."), ".")
+		return json.Marshal(&addRowWDMP{Command: CommandAddRow, Table: c.Row[:instance+1], Row: row})
+	}
+
+	return nil, fmt.Errorf("%s cannot be rolled back", c.Command)
+}
+
+// encodeScriptResponse responds with the outcome of each step, using the
+// status of the failed command, if any.
+func encodeScriptResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(*scriptResponse)
+
+	w.Header().Set(contentTypeHeaderKey, "application/json")
+	w.Header().Set(candlelight.HeaderWPATIDKeyName, getTID(ctx))
+	w.WriteHeader(resp.statusCode)
+	return json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,222 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+const testScript = `{"rollback": true, "steps": [
+	{"command": "SET", "parameters": [{"name": "Device.A", "value": "new", "dataType": 0}]},
+	{"command": "ADD_ROW", "table": "Device.T.", "row": {"Name": "y"}},
+	{"command": "DELETE_ROW", "row": "Device.T.1."},
+	{"command": "REPLACE_ROWS", "table": "Device.R.", "rows": {"0": {"Name": "z"}}}
+]}`
+
+func decodeTestScript(script string) (interface{}, error) {
+	r := httptest.NewRequest(http.MethodPost, "http://localhost/api/v3/device/mac:112233445566/config/script", bytes.NewBufferString(script))
+	r = mux.SetURLVars(r, map[string]string{"deviceid": "mac:112233445566", "service": "config"})
+	schemas := TableSchemas{{Table: "Device.T.", Columns: []string{"Name"}}, {Table: "Device.R.", Columns: []string{"Name"}}}
+	return decodeScriptRequest(ScriptConfig{MaxSteps: 4}, schemas)(captureRequestBody(BodyLimits{})(ctxTID, r), r)
+}
+
+func TestDecodeScriptRequest(t *testing.T) {
+	tests := []struct {
+		description string
+		script      string
+		expectedErr string
+	}{
+		{
+			description: "valid",
+			script:      testScript,
+		},
+		{
+			description: "invalid",
+			script:      `{"steps": [{"command": "SET", "name": "Device.A"}]}`,
+			expectedErr: ErrInvalidScript.Error(),
+		},
+		{
+			description: "no steps",
+			script:      `{"rollback": true, "steps": []}`,
+			expectedErr: ErrMissingScriptSteps.Error(),
+		},
+		{
+			description: "too many steps",
+			script:      `{"steps": [{"command": "DELETE_ROW", "row": "T.1."}, {"command": "DELETE_ROW", "row": "T.2."}, {"command": "DELETE_ROW", "row": "T.3."}, {"command": "DELETE_ROW", "row": "T.4."}, {"command": "DELETE_ROW", "row": "T.5."}]}`,
+			expectedErr: ErrTooManyScriptSteps.Error(),
+		},
+		{
+			description: "unsupported command",
+			script:      `{"steps": [{"command": "GET", "parameters": []}]}`,
+			expectedErr: "step 1: unsupported command 'GET', expected one of SET, ADD_ROW, REPLACE_ROWS or DELETE_ROW",
+		},
+		{
+			description: "SET_ATTRIBUTES step",
+			script:      `{"steps": [{"command": "DELETE_ROW", "row": "T.1."}, {"command": "SET", "parameters": [{"name": "Device.A", "attributes": {"notify": 1}}]}]}`,
+			expectedErr: "step 2: " + ErrInvalidSetWDMP.Error(),
+		},
+		{
+			description: "column not in the table schema",
+			script:      `{"steps": [{"command": "DELETE_ROW", "row": "T.1."}, {"command": "REPLACE_ROWS", "table": "Device.R.", "rows": {"0": {"Port": "80"}}}]}`,
+			expectedErr: "step 2: column 'Port' is not in the schema of table 'Device.R.'",
+		},
+		{
+			description: "invalid row",
+			script:      `{"steps": [{"command": "DELETE_ROW", "row": "T."}]}`,
+			expectedErr: "step 1: invalid row 'T.', expected a table instance such as 'Device.WiFi.AccessPoint.1.'",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			decoded, err := decodeTestScript(tc.script)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			req := decoded.(*scriptRequest)
+			assert.True(t, req.Rollback)
+			require.Len(t, req.Commands, 4)
+			assert.Equal(t, []string{"Device.A"}, req.Commands[0].Names)
+			assert.Equal(t, "Device.T.", req.Commands[1].Table)
+			assert.Equal(t, "Device.T.1.", req.Commands[2].Row)
+			assert.JSONEq(t, `{"command": "REPLACE_ROWS", "table": "Device.R.", "rows": {"0": {"Name": "z"}}}`, string(req.Commands[3].Payload))
+		})
+	}
+}
+
+// scriptDevice answers WDMP commands with the payload set for their command
+// and records the commands it got.
+type scriptDevice struct {
+	responses map[string]string
+	sent      []string
+}
+
+func (d *scriptDevice) SendWRP(_ context.Context, msg *wrp.Message, _ string) (*transaction.XmidtResponse, error) {
+	var wdmp struct {
+		Command string `json:"command"`
+	}
+	_ = json.Unmarshal(msg.Payload, &wdmp)
+	d.sent = append(d.sent, string(msg.Payload))
+
+	return &transaction.XmidtResponse{
+		Code: http.StatusOK,
+		Body: wrp.MustEncode(&wrp.Message{Type: wrp.SimpleRequestResponseMessageType, Payload: []byte(d.responses[wdmp.Command])}, wrp.Msgpack),
+	}, nil
+}
+
+func newScriptDevice() *scriptDevice {
+	return &scriptDevice{responses: map[string]string{
+		CommandGet: `{"statusCode": 200, "parameters": [
+			{"name": "Device.A", "value": "old", "dataType": 0},
+			{"name": "Device.T.1.", "value": [{"name": "Device.T.1.Name", "value": "x", "dataType": 0}, {"name": "Device.T.1.Port", "value": "80", "dataType": 2}], "dataType": 11},
+			{"name": "Device.R.", "value": [{"name": "Device.R.0.Name", "value": "w", "dataType": 0}], "dataType": 11}
+		]}`,
+		CommandSet:         `{"statusCode": 200, "message": "Success"}`,
+		CommandAddRow:      `{"statusCode": 201, "message": "Success", "row": "Device.T.5."}`,
+		CommandDeleteRow:   `{"statusCode": 200, "message": "Success"}`,
+		CommandReplaceRows: `{"statusCode": 520, "message": "Failure"}`,
+	}}
+}
+
+func TestScriptEndpoint(t *testing.T) {
+	t.Run("rollback", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		req, err := decodeTestScript(testScript)
+		require.NoError(err)
+
+		device := newScriptDevice()
+		resp, err := makeScriptEndpoint(device)(ctxTID, req)
+		require.NoError(err)
+
+		require.Len(device.sent, 8)
+		assert.JSONEq(`{"command": "GET", "names": ["Device.A", "Device.T.1.", "Device.R."]}`, device.sent[0])
+		assert.JSONEq(`{"command": "ADD_ROW", "table": "Device.T.", "row": {"Name": "x", "Port": "80"}}`, device.sent[5])
+		assert.JSONEq(`{"command": "DELETE_ROW", "row": "Device.T.5."}`, device.sent[6])
+		assert.JSONEq(`{"command": "SET", "parameters": [{"name": "Device.A", "value": "old", "dataType": 0}]}`, device.sent[7])
+
+		w := httptest.NewRecorder()
+		require.NoError(encodeScriptResponse(ctxTID, w, resp))
+		assert.Equal(520, w.Code)
+		assert.JSONEq(`{"steps": [
+			{"command": "SET", "status": "rolledBack", "statusCode": 200, "message": "Success", "rollback": {"statusCode": 200, "message": "Success"}},
+			{"command": "ADD_ROW", "status": "rolledBack", "statusCode": 201, "message": "Success", "rollback": {"statusCode": 200, "message": "Success"}},
+			{"command": "DELETE_ROW", "status": "rolledBack", "statusCode": 200, "message": "Success", "rollback": {"statusCode": 201, "message": "Success"}},
+			{"command": "REPLACE_ROWS", "status": "failed", "statusCode": 520, "message": "Failure"}
+		]}`, w.Body.String())
+	})
+
+	t.Run("no rollback", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		req, err := decodeTestScript(`{"steps": [
+			{"command": "ADD_ROW", "table": "Device.T.", "row": {"Name": "y"}},
+			{"command": "REPLACE_ROWS", "table": "Device.R.", "rows": {"0": {"Name": "z"}}},
+			{"command": "DELETE_ROW", "row": "Device.T.1."}
+		]}`)
+		require.NoError(err)
+
+		device := newScriptDevice()
+		resp, err := makeScriptEndpoint(device)(ctxTID, req)
+		require.NoError(err)
+		assert.Len(device.sent, 3)
+
+		steps := resp.(*scriptResponse).Steps
+		assert.Equal([]string{StepApplied, StepFailed, StepSkipped}, []string{steps[0].Status, steps[1].Status, steps[2].Status})
+	})
+
+	t.Run("capture failure", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		req, err := decodeTestScript(testScript)
+		require.NoError(err)
+
+		device := newScriptDevice()
+		device.responses[CommandGet] = `{"statusCode": 520, "message": "Invalid parameter name"}`
+		resp, err := makeScriptEndpoint(device)(ctxTID, req)
+		require.NoError(err)
+		assert.Len(device.sent, 1)
+
+		w := httptest.NewRecorder()
+		require.NoError(encodeScriptResponse(ctxTID, w, resp))
+		assert.Equal(520, w.Code)
+		assert.JSONEq(`{"capture": {"statusCode": 520, "message": "Invalid parameter name"}, "steps": [
+			{"command": "SET", "status": "skipped", "statusCode": 0},
+			{"command": "ADD_ROW", "status": "skipped", "statusCode": 0},
+			{"command": "DELETE_ROW", "status": "skipped", "statusCode": 0},
+			{"command": "REPLACE_ROWS", "status": "skipped", "statusCode": 0}
+		]}`, w.Body.String())
+	})
+}
+
+func TestReplaceRowsRollbackPayload(t *testing.T) {
+	captured := make(map[string]capturedParam)
+	flattenParams([]capturedParam{{Name: "Device.R.", Value: json.RawMessage(`[
+		{"name": "Device.R.1.Name", "value": "a", "dataType": 0},
+		{"name": "Device.R.1.Sub.", "value": [{"name": "Device.R.1.Sub.1.X", "value": "s", "dataType": 0}], "dataType": 11},
+		{"name": "Device.R.2.Name", "value": "b", "dataType": 0},
+		{"name": "Device.R.2.Enable", "value": true, "dataType": 3}
+	]`)}}, captured)
+
+	payload, err := scriptCommand{Command: CommandReplaceRows, Table: "Device.R."}.rollbackPayload(captured, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"command": "REPLACE_ROWS", "table": "Device.R.", "rows": {"1": {"Name": "a"}, "2": {"Name": "b", "Enable": "true"}}}`, string(payload))
+}
@@ -6,6 +6,7 @@ package translation
 import (
 	"bytes"
 	"context"
+	"strings"
 
 	"net/http"
 
@@ -31,6 +32,15 @@ type ServiceOptions struct {
 	//Acquirer provides a mechanism to build auth headers for outbound requests.
 	AuthAcquirer transaction.AuthAcquirer
 
+	//AuthPolicy chooses, per device service, whether outbound requests forward the
+	//caller's credentials, use the AuthAcquirer or use a token from the TokenExchanger.
+	//(Optional) By default, the AuthAcquirer is used if set.
+	AuthPolicy transaction.AuthPolicy
+
+	//TokenExchanger mints downstream tokens carrying the caller's identity.
+	//(Optional)
+	TokenExchanger transaction.TokenExchanger
+
 	//T is the component that's responsible to make the HTTP
 	//request to the XMiDT API and return only data we care about.
 	transaction.T
@@ -39,23 +49,33 @@ type ServiceOptions struct {
 // NewService constructs a new translation service instance given some options.
 func NewService(o *ServiceOptions) Service {
 	return &service{
-		xmidtWrpURL:  o.XmidtWrpURL,
-		wrpSource:    o.WRPSource,
-		transactor:   o.T,
-		authAcquirer: o.AuthAcquirer,
+		xmidtWrpURL: o.XmidtWrpURL,
+		wrpSource:   o.WRPSource,
+		transactor:  o.T,
+		auth: transaction.OutboundAuth{
+			Policy:    o.AuthPolicy,
+			Acquirer:  o.AuthAcquirer,
+			Exchanger: o.TokenExchanger,
+		},
 	}
 }
 
 type service struct {
-	transactor   transaction.T
-	authAcquirer transaction.AuthAcquirer
-	xmidtWrpURL  string
-	wrpSource    string
+	transactor  transaction.T
+	auth        transaction.OutboundAuth
+	xmidtWrpURL string
+	wrpSource   string
 }
 
 // SendWRP sends the given wrpMsg to the XMiDT cluster and returns the response if any.
 func (w *service) SendWRP(ctx context.Context, wrpMsg *wrp.Message, authHeaderValue string) (*transaction.XmidtResponse, error) {
 	wrpMsg.Source = w.wrpSource
+	if d, ok := transaction.GetRequestTimeout(ctx); ok {
+		if wrpMsg.Metadata == nil {
+			wrpMsg.Metadata = make(map[string]string)
+		}
+		wrpMsg.Metadata[wrpTimeoutMetadataKey] = d.String()
+	}
 
 	var payload []byte
 
@@ -71,14 +91,25 @@ func (w *service) SendWRP(ctx context.Context, wrpMsg *wrp.Message, authHeaderVa
 		return nil, err
 	}
 
-	if w.authAcquirer != nil {
-		authHeaderValue, err = w.authAcquirer.Acquire()
-		if err != nil {
-			return nil, err
-		}
+	authHeaderValue, err = w.auth.Authorization(ctx, transaction.APIDevice, destinationService(wrpMsg.Destination), authHeaderValue)
+	if err != nil {
+		return nil, err
 	}
 
 	r.Header.Set("Content-Type", wrp.Msgpack.ContentType())
 	r.Header.Set("Authorization", authHeaderValue)
 	return w.transactor.Transact(r)
 }
+
+// wrpTimeoutMetadataKey is the WRP metadata key carrying the timeout of the
+// request, for the cluster and device to give up on it in time. WRP has no
+// timeout field of its own, its QOS being a delivery priority.
+const wrpTimeoutMetadataKey = "timeout"
+
+// destinationService returns the service part of a WRP destination of the
+// form "{deviceid}/{service}/...".
+func destinationService(destination string) string {
+	_, service, _ := strings.Cut(destination, "/")
+	service, _, _ = strings.Cut(service, "/")
+	return service
+}
@@ -10,10 +10,12 @@ import (
 	"io/ioutil"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/tr1d1um/transaction"
 	"github.com/xmidt-org/wrp-go/v3"
 )
 
@@ -110,3 +112,34 @@ func TestSendWRP(t *testing.T) {
 		})
 	}
 }
+
+func TestSendWRPRequestTimeout(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	m := new(MockTr1d1umTransactor)
+	s := NewService(&ServiceOptions{
+		XmidtWrpURL: "http://localhost/wrp",
+		WRPSource:   "dns:tr1d1um-xyz-example.com",
+		T:           m,
+	})
+
+	// the matcher runs more than once, the body is read once and restored
+	var requestMatcher = func(r *http.Request) bool {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(err)
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		var msg wrp.Message
+		require.NoError(wrp.NewDecoderBytes(body, wrp.Msgpack).Decode(&msg))
+		assert.Empty(msg.Headers)
+		assert.Equal(map[string]string{"timeout": "5s"}, msg.Metadata)
+		return true
+	}
+	m.On("Transact", mock.MatchedBy(requestMatcher)).Return(nil, nil)
+
+	ctx := transaction.WithRequestTimeout(context.Background(), 5*time.Second)
+	_, err := s.SendWRP(ctx, &wrp.Message{Type: wrp.SimpleRequestResponseMessageType}, "pass-through-token")
+	assert.NoError(err)
+	m.AssertExpectations(t)
+}
@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/xmidt-org/tr1d1um/transaction"
+)
+
+// TableInstance is the segment of a TableSchema table path matching any
+// instance number, e.g. "Device.WiFi.AccessPoint.{i}.AssociatedDevice.".
+const TableInstance = "{i}"
+
+var (
+	tablePathPattern = regexp.MustCompile(`^([A-Za-z0-9_-]+\.)+$`)
+	rowPathPattern   = regexp.MustCompile(`^([A-Za-z0-9_-]+\.)+[0-9]+\.$`)
+	columnPattern    = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// TableSchema lists the columns rows of a table may have.
+type TableSchema struct {
+	// Table is the path of the table, ending with a '.'. TableInstance
+	// segments match any instance number.
+	Table string
+
+	// Columns lists the column names of the rows of the table.
+	Columns []string
+}
+
+// TableSchemas are the known table schemas. Rows of tables without a schema
+// may have any valid column names.
+type TableSchemas []TableSchema
+
+// validateTablePath checks that a table path ends with a '.'.
+func validateTablePath(table string) error {
+	if !tablePathPattern.MatchString(table) {
+		return transaction.NewBadRequestError(fmt.Errorf("invalid table '%s', expected a path ending with '.' such as 'Device.WiFi.AccessPoint.'", table))
+	}
+	return nil
+}
+
+// validateRowPath checks that a row path is a table instance, 'Table.N.'.
+func validateRowPath(row string) error {
+	if !rowPathPattern.MatchString(row) {
+		return transaction.NewBadRequestError(fmt.Errorf("invalid row '%s', expected a table instance such as 'Device.WiFi.AccessPoint.1.'", row))
+	}
+	return nil
+}
+
+// validateRowIndex checks that a REPLACE_ROWS row index is an instance number.
+func validateRowIndex(index string) error {
+	if n, err := strconv.Atoi(index); err != nil || n < 0 {
+		return transaction.NewBadRequestError(fmt.Errorf("invalid row index '%s', expected an instance number", index))
+	}
+	return nil
+}
+
+// validateColumns checks the column names of a row.
+func validateColumns[V any](row map[string]V) error {
+	if len(row) == 0 {
+		return transaction.NewBadRequestError(errors.New("row must have at least one column"))
+	}
+	for _, column := range slices.Sorted(maps.Keys(row)) {
+		if !columnPattern.MatchString(column) {
+			return transaction.NewBadRequestError(fmt.Errorf("invalid column name '%s'", column))
+		}
+	}
+	return nil
+}
+
+// schema returns the schema of a table, if any.
+func (s TableSchemas) schema(table string) (TableSchema, bool) {
+	for _, ts := range s {
+		if tableMatches(ts.Table, table) {
+			return ts, true
+		}
+	}
+	return TableSchema{}, false
+}
+
+// tableMatches reports whether a table path matches a schema table path.
+func tableMatches(pattern, table string) bool {
+	p, t := strings.Split(pattern, "."), strings.Split(table, ".")
+	if len(p) != len(t) {
+		return false
+	}
+	for i := range p {
+		if p[i] == TableInstance {
+			if _, err := strconv.Atoi(t[i]); err != nil {
+				return false
+			}
+		} else if p[i] != t[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// validateRow checks the columns of a row against the schema of its table.
+func (s TableSchemas) validateRow(table string, row map[string]json.RawMessage) error {
+	ts, ok := s.schema(table)
+	if !ok {
+		return nil
+	}
+	for _, column := range slices.Sorted(maps.Keys(row)) {
+		if !slices.Contains(ts.Columns, column) {
+			return transaction.NewBadRequestError(fmt.Errorf("column '%s' is not in the schema of table '%s'", column, table))
+		}
+	}
+	return nil
+}
+
+// DecodeTableSchemas returns a decoder that checks the rows of ADD_ROW and
+// REPLACE_ROWS commands decoded by decoder against the table schemas.
+func DecodeTableSchemas(s TableSchemas, decoder kithttp.DecodeRequestFunc) kithttp.DecodeRequestFunc {
+	return func(ctx context.Context, r *http.Request) (interface{}, error) {
+		decodedRequest, err := decoder(ctx, r)
+		if err != nil || len(s) == 0 {
+			return decodedRequest, err
+		}
+
+		wrpReq, ok := decodedRequest.(*wrpRequest)
+		if !ok {
+			return decodedRequest, nil
+		}
+		if err = s.validatePayload(wrpReq.WRPMessage.Payload); err != nil {
+			return nil, err
+		}
+
+		return decodedRequest, nil
+	}
+}
+
+// validatePayload checks the rows of an ADD_ROW or REPLACE_ROWS WDMP payload
+// against the table schemas. Other payloads are left alone.
+func (s TableSchemas) validatePayload(payload []byte) error {
+	var wdmp struct {
+		Command string                                `json:"command"`
+		Table   string                                `json:"table"`
+		Row     map[string]json.RawMessage            `json:"row"`
+		Rows    map[string]map[string]json.RawMessage `json:"rows"`
+	}
+	if len(s) == 0 || json.Unmarshal(payload, &wdmp) != nil {
+		return nil
+	}
+
+	switch wdmp.Command {
+	case CommandAddRow:
+		return s.validateRow(wdmp.Table, wdmp.Row)
+	case CommandReplaceRows:
+		for _, index := range slices.Sorted(maps.Keys(wdmp.Rows)) {
+			if err := s.validateRow(wdmp.Table, wdmp.Rows[index]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+func TestTablePayloadValidation(t *testing.T) {
+	tests := []struct {
+		description string
+		payload     func() ([]byte, error)
+		expectedErr string
+	}{
+		{
+			description: "add row to invalid table",
+			payload: func() ([]byte, error) {
+				return requestAddPayload(map[string]string{"parameter": "Device.WiFi.AccessPoint"}, bytes.NewBufferString(`{"SSID": "home"}`))
+			},
+			expectedErr: "invalid table 'Device.WiFi.AccessPoint', expected a path ending with '.' such as 'Device.WiFi.AccessPoint.'",
+		},
+		{
+			description: "add row with invalid column",
+			payload: func() ([]byte, error) {
+				return requestAddPayload(map[string]string{"parameter": "Device.WiFi.AccessPoint."}, bytes.NewBufferString(`{"Security.Mode": "WPA2"}`))
+			},
+			expectedErr: "invalid column name 'Security.Mode'",
+		},
+		{
+			description: "add empty row",
+			payload: func() ([]byte, error) {
+				return requestAddPayload(map[string]string{"parameter": "Device.WiFi.AccessPoint."}, bytes.NewBufferString(`{}`))
+			},
+			expectedErr: "row must have at least one column",
+		},
+		{
+			description: "replace rows of invalid table",
+			payload: func() ([]byte, error) {
+				return requestReplacePayload(map[string]string{"parameter": "Device..Table."}, bytes.NewBufferString(`{"0": {"SSID": "home"}}`))
+			},
+			expectedErr: "invalid table 'Device..Table.', expected a path ending with '.' such as 'Device.WiFi.AccessPoint.'",
+		},
+		{
+			description: "replace rows with invalid index",
+			payload: func() ([]byte, error) {
+				return requestReplacePayload(map[string]string{"parameter": "Device.WiFi.AccessPoint."}, bytes.NewBufferString(`{"first": {"SSID": "home"}}`))
+			},
+			expectedErr: "invalid row index 'first', expected an instance number",
+		},
+		{
+			description: "delete invalid row",
+			payload: func() ([]byte, error) {
+				return requestDeletePayload(map[string]string{"parameter": "Device.WiFi.AccessPoint."})
+			},
+			expectedErr: "invalid row 'Device.WiFi.AccessPoint.', expected a table instance such as 'Device.WiFi.AccessPoint.1.'",
+		},
+		{
+			description: "delete row",
+			payload: func() ([]byte, error) {
+				return requestDeletePayload(map[string]string{"parameter": "Device.WiFi.AccessPoint.2."})
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := tc.payload()
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestTableMatches(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(tableMatches("Device.WiFi.AccessPoint.", "Device.WiFi.AccessPoint."))
+	assert.True(tableMatches("Device.WiFi.AccessPoint.{i}.AssociatedDevice.", "Device.WiFi.AccessPoint.10.AssociatedDevice."))
+	assert.False(tableMatches("Device.WiFi.AccessPoint.{i}.AssociatedDevice.", "Device.WiFi.AccessPoint.x.AssociatedDevice."))
+	assert.False(tableMatches("Device.WiFi.AccessPoint.", "Device.WiFi.AccessPoint.1."))
+}
+
+func TestDecodeTableSchemas(t *testing.T) {
+	schemas := TableSchemas{
+		{Table: "Device.WiFi.AccessPoint.{i}.MacFilter.", Columns: []string{"MACAddress", "DeviceName"}},
+	}
+
+	tests := []struct {
+		description string
+		wdmp        interface{}
+		expectedErr string
+	}{
+		{
+			description: "add row in schema",
+			wdmp:        &addRowWDMP{Command: CommandAddRow, Table: "Device.WiFi.AccessPoint.1.MacFilter.", Row: map[string]string{"MACAddress": "11:22:33:44:55:66"}},
+		},
+		{
+			description: "add row not in schema",
+			wdmp:        &addRowWDMP{Command: CommandAddRow, Table: "Device.WiFi.AccessPoint.1.MacFilter.", Row: map[string]string{"MAC": "11:22:33:44:55:66"}},
+			expectedErr: "column 'MAC' is not in the schema of table 'Device.WiFi.AccessPoint.1.MacFilter.'",
+		},
+		{
+			description: "replace rows not in schema",
+			wdmp: &replaceRowsWDMP{Command: CommandReplaceRows, Table: "Device.WiFi.AccessPoint.2.MacFilter.", Rows: indexRow{
+				"0": {"DeviceName": "tv"},
+				"1": {"Name": "phone"},
+			}},
+			expectedErr: "column 'Name' is not in the schema of table 'Device.WiFi.AccessPoint.2.MacFilter.'",
+		},
+		{
+			description: "table without schema",
+			wdmp:        &addRowWDMP{Command: CommandAddRow, Table: "Device.Hosts.Host.", Row: map[string]string{"Any": "value"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			payload, err := json.Marshal(tc.wdmp)
+			assert.NoError(t, err)
+
+			decode := DecodeTableSchemas(schemas, func(context.Context, *http.Request) (interface{}, error) {
+				return &wrpRequest{WRPMessage: &wrp.Message{Payload: payload}}, nil
+			})
+
+			_, err = decode(context.Background(), httptest.NewRequest(http.MethodPost, "http://localhost", nil))
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
@@ -10,13 +10,14 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"maps"
 	"net/http"
+	"slices"
 	"strings"
 
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/gorilla/mux"
 	"github.com/justinas/alice"
-	"github.com/spf13/cast"
 	"go.uber.org/zap"
 
 	"github.com/xmidt-org/bascule"
@@ -32,6 +33,14 @@ const (
 	authHeaderKey        = "Authorization"
 )
 
+type contextKey int
+
+// Keys to values the translation server puts in request contexts
+const (
+	contextKeyResponseMediaType contextKey = iota
+	contextKeyRequestBody
+)
+
 // Options wraps the properties needed to set up the translation server
 type Options struct {
 	S Service
@@ -41,33 +50,129 @@ type Options struct {
 
 	Authenticate                *alice.Chain
 	Log                         *zap.Logger
-	ValidServices               []string
-	ReducedLoggingResponseCodes []int
-	BearerFingerprint           transaction.FingerprintConfig
+	ValidServices               func() []string
+	ReducedLoggingResponseCodes func() []int
+	BearerFingerprint           func() transaction.FingerprintConfig
+
+	//Timeouts bounds the timeouts clients may request with the X-Webpa-Timeout header.
+	Timeouts transaction.TimeoutPolicy
+
+	//StrictAccept rejects the requests accepting none of the supported media
+	//types with a 406, instead of responding with the payload alone.
+	StrictAccept bool
+
+	//WRPPassthrough lists the WRP message fields clients may set through headers.
+	WRPPassthrough WRPPassthrough
+
+	//RawServices lists the device services that accept raw WRP requests.
+	RawServices []string
+
+	//Events configures the endpoints sending fire-and-forget events to devices.
+	Events EventConfig
+
+	//EventMetrics records the delivery of events to XMiDT.
+	EventMetrics EventMetrics
+
+	//BatchGet configures the endpoint paging through large GETs.
+	BatchGet BatchGetConfig
+
+	//BodyLimits bounds the size of request bodies.
+	BodyLimits BodyLimits
+
+	//TableSchemas lists the columns the rows of known tables may have.
+	TableSchemas TableSchemas
+
+	//Scripts configures the endpoint running scripts of WDMP commands.
+	Scripts ScriptConfig
+
+	//CompareAndSwap configures the compare-and-swap mode of SET requests.
+	CompareAndSwap CompareAndSwapConfig
 }
 
 // ConfigHandler sets up the server that powers the translation service
 func ConfigHandler(c *Options) {
 	opts := []kithttp.ServerOption{
+		kithttp.ServerBefore(captureRequestBody(c.BodyLimits)),
 		kithttp.ServerBefore(captureWDMPParameters),
+		kithttp.ServerBefore(transaction.CaptureRequestTimeout(c.Timeouts, deviceService)),
+		kithttp.ServerBefore(captureResponseMediaType),
 		kithttp.ServerErrorEncoder(transaction.ErrorLogEncoder(sallust.Get, encodeError)),
-		kithttp.ServerFinalizer(transaction.Log(c.ReducedLoggingResponseCodes)),
+		kithttp.ServerFinalizer(transaction.LogFunc(c.ReducedLoggingResponseCodes)),
 	}
 
 	WRPHandler := kithttp.NewServer(
 		makeTranslationEndpoint(c.S),
-		decodeValidServiceRequest(c.ValidServices, decodeRequest),
+		decodeValidServiceRequest(c.ValidServices, decodeRequestBody(decodeAcceptableRequest(c.StrictAccept, transaction.DecodeRequestTimeout(DecodeWRPPassthrough(c.WRPPassthrough, DecodeTableSchemas(c.TableSchemas, decodeRequest)))))),
 		encodeResponse,
 		opts...,
 	)
 
-	welcome := transaction.Welcome(c.BearerFingerprint)
+	RawHandler := kithttp.NewServer(
+		makeTranslationEndpoint(c.S),
+		decodeValidServiceRequest(func() []string { return c.RawServices }, decodeRequestBody(transaction.DecodeRequestTimeout(DecodeWRPPassthrough(c.WRPPassthrough, decodeRawRequest)))),
+		encodeRawResponse,
+		opts...,
+	)
+
+	EventHandler := kithttp.NewServer(
+		makeEventEndpoint(c.S, c.EventMetrics),
+		decodeValidServiceRequest(func() []string { return c.Events.Services }, decodeRequestBody(transaction.DecodeRequestTimeout(DecodeWRPPassthrough(c.WRPPassthrough, decodeEventRequest(c.Events.MaxDevices))))),
+		encodeEventResponse,
+		opts...,
+	)
+
+	BatchGetHandler := kithttp.NewServer(
+		makeBatchGetEndpoint(c.S, c.BatchGet),
+		decodeValidServiceRequest(c.ValidServices, transaction.DecodeRequestTimeout(DecodeWRPPassthrough(c.WRPPassthrough, decodeBatchGetRequest(c.BatchGet)))),
+		encodeBatchGetResponse,
+		opts...,
+	)
+
+	ScriptHandler := kithttp.NewServer(
+		makeScriptEndpoint(c.S),
+		decodeValidServiceRequest(c.ValidServices, decodeRequestBody(transaction.DecodeRequestTimeout(decodeScriptRequest(c.Scripts, c.TableSchemas)))),
+		encodeScriptResponse,
+		opts...,
+	)
+
+	CASHandler := kithttp.NewServer(
+		makeCASEndpoint(c.S, c.CompareAndSwap),
+		decodeValidServiceRequest(c.ValidServices, decodeRequestBody(decodeAcceptableRequest(c.StrictAccept, transaction.DecodeRequestTimeout(DecodeWRPPassthrough(c.WRPPassthrough, DecodeTableSchemas(c.TableSchemas, decodeCASRequest)))))),
+		encodeCASResponse,
+		opts...,
+	)
+
+	welcome := transaction.WelcomeFunc(c.BearerFingerprint)
+
+	c.APIRouter.Handle("/device/{deviceid}/{service}", c.Authenticate.Then(candlelight.EchoFirstTraceNodeInfo(candlelight.Tracing{}.Propagator(), false)(welcome(CASHandler)))).
+		Methods(http.MethodPatch).
+		HeadersRegexp(HeaderWPASyncMode, "(?i)^"+SyncModeCAS+"$")
 
 	c.APIRouter.Handle("/device/{deviceid}/{service}", c.Authenticate.Then(candlelight.EchoFirstTraceNodeInfo(candlelight.Tracing{}.Propagator(), false)(welcome(WRPHandler)))).
 		Methods(http.MethodGet, http.MethodPatch)
 
+	c.APIRouter.Handle("/device/{deviceid}/{service}/batch", c.Authenticate.Then(candlelight.EchoFirstTraceNodeInfo(candlelight.Tracing{}.Propagator(), false)(welcome(BatchGetHandler)))).
+		Methods(http.MethodGet)
+
+	c.APIRouter.Handle("/device/{deviceid}/{service}/script", c.Authenticate.Then(candlelight.EchoFirstTraceNodeInfo(candlelight.Tracing{}.Propagator(), false)(welcome(ScriptHandler)))).
+		Methods(http.MethodPost)
+
 	c.APIRouter.Handle("/device/{deviceid}/{service}/{parameter}", c.Authenticate.Then(candlelight.EchoFirstTraceNodeInfo(candlelight.Tracing{}.Propagator(), false)(welcome(WRPHandler)))).
 		Methods(http.MethodDelete, http.MethodPut, http.MethodPost)
+
+	c.APIRouter.Handle("/wrp/device/{deviceid}/{service}", c.Authenticate.Then(candlelight.EchoFirstTraceNodeInfo(candlelight.Tracing{}.Propagator(), false)(welcome(RawHandler)))).
+		Methods(http.MethodPost)
+
+	c.APIRouter.Handle("/event/device/{deviceid}/{service}", c.Authenticate.Then(candlelight.EchoFirstTraceNodeInfo(candlelight.Tracing{}.Propagator(), false)(welcome(EventHandler)))).
+		Methods(http.MethodPost)
+
+	c.APIRouter.Handle("/event/devices/{service}", c.Authenticate.Then(candlelight.EchoFirstTraceNodeInfo(candlelight.Tracing{}.Propagator(), false)(welcome(EventHandler)))).
+		Methods(http.MethodPost)
+}
+
+// deviceService returns the device service a request is for.
+func deviceService(r *http.Request) string {
+	return mux.Vars(r)["service"]
 }
 
 // getPartnerIDs returns the array that represents the partner-ids that were
@@ -97,25 +202,8 @@ func getPartnerIDsDecodeRequest(ctx context.Context, r *http.Request) []string {
 	if !ok {
 		return getPartnerIDs(r.Header)
 	}
-	// Try to access token attributes
-	if accessor, ok := auth.(bascule.AttributesAccessor); ok {
-		// First try simple top-level partner keys
-		for _, key := range transaction.PartnerKeys() {
-			if partnerVal, found := accessor.Get(key); found {
-				partnerIDs, err := cast.ToStringSliceE(partnerVal)
-				if err == nil {
-					return partnerIDs
-				}
-			}
-		}
-		// Try nested path: allowedResources.allowedPartners
-		partnerIDs, ok := bascule.GetAttribute[[]interface{}](accessor, "allowedResources", "allowedPartners")
-		if ok && len(partnerIDs) > 0 {
-			strIDs, err := cast.ToStringSliceE(partnerIDs)
-			if err == nil {
-				return strIDs
-			}
-		}
+	if partnerIDs, ok := transaction.TokenPartnerIDs(auth); ok {
+		return partnerIDs
 	}
 	// Fallback to headers
 	return getPartnerIDs(r.Header)
@@ -131,6 +219,24 @@ func getTID(ctx context.Context) string {
 	return t
 }
 
+// getTraceHeaders returns the WRP headers carrying the trace context of the request.
+func getTraceHeaders(h http.Header) []string {
+	var traceHeaders []string
+
+	// If there's a traceparent, add it to traceHeaders array
+	// Also, add tracestate to the traceHeaders array (can be empty)
+	// A tracestate will not exist without a traceparent
+	tp := h.Get("traceparent")
+	if tp != "" {
+		tp = "traceparent: " + tp
+		ts := h.Get("tracestate")
+		ts = "tracestate: " + ts
+		traceHeaders = append(traceHeaders, tp, ts)
+	}
+
+	return traceHeaders
+}
+
 /* Request Decoding */
 func decodeRequest(ctx context.Context, r *http.Request) (decodedRequest interface{}, err error) {
 	var (
@@ -146,20 +252,7 @@ func decodeRequest(ctx context.Context, r *http.Request) (decodedRequest interfa
 	}
 
 	if err == nil {
-		var traceHeaders []string
-
-		// If there's a traceparent, add it to traceHeaders array
-		// Also, add tracestate to the traceHeaders array (can be empty)
-		// A tracestate will not exist without a traceparent
-		tp := r.Header.Get("traceparent")
-		if tp != "" {
-			tp = "traceparent: " + tp
-			ts := r.Header.Get("tracestate")
-			ts = "tracestate: " + ts
-			traceHeaders = append(traceHeaders, tp, ts)
-		}
-
-		wrpMsg, err = wrap(payload, tid, mux.Vars(r), partnerIDs, traceHeaders)
+		wrpMsg, err = wrap(payload, tid, mux.Vars(r), partnerIDs, getTraceHeaders(r.Header))
 
 		if err == nil {
 			decodedRequest = &wrpRequest{
@@ -223,7 +316,20 @@ func encodeResponse(ctx context.Context, w http.ResponseWriter, response interfa
 			StatusCode int `json:"statusCode"`
 		}
 
-		w.Header().Set("Content-Type", "application/json")
+		// the full WRP message is sent as is for msgpack, or re-encoded for JSON
+		mediaType, body := responseMediaType(ctx), wrpModel.Payload
+		switch mediaType {
+		case MediaTypeWRPMsgpack:
+			body = resp.Body
+		case MediaTypeWRPJSON:
+			body = nil
+			if err = wrp.NewEncoderBytes(&body, wrp.JSON).Encode(wrpModel); err != nil {
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", mediaType)
+		w.Header().Add("Vary", "Accept")
 		// use the device response status code if it's within 520-599 (inclusive) or 403
 		// https://github.com/xmidt-org/tr1d1um/issues/354
 		// https://github.com/xmidt-org/tr1d1um/issues/397
@@ -233,7 +339,7 @@ func encodeResponse(ctx context.Context, w http.ResponseWriter, response interfa
 			}
 		}
 
-		_, err = w.Write(wrpModel.Payload)
+		_, err = w.Write(body)
 	}
 
 	return
@@ -305,6 +411,10 @@ func requestAddPayload(m map[string]string, input io.Reader) (p []byte, err erro
 		return nil, ErrMissingTable
 	}
 
+	if err = validateTablePath(table); err != nil {
+		return nil, err
+	}
+
 	wdmp.Table = table
 
 	payload, err := ioutil.ReadAll(input)
@@ -317,10 +427,19 @@ func requestAddPayload(m map[string]string, input io.Reader) (p []byte, err erro
 		return nil, ErrMissingRow
 	}
 
-	err = json.Unmarshal(payload, &wdmp.Row)
+	var row map[string]json.RawMessage
+	err = strictUnmarshal(payload, &row)
 	if err != nil {
 		return nil, ErrInvalidRow
 	}
+
+	if err = validateColumns(row); err != nil {
+		return nil, err
+	}
+
+	if wdmp.Row, err = normalizeRow(row); err != nil {
+		return nil, err
+	}
 	return json.Marshal(wdmp)
 }
 
@@ -332,6 +451,10 @@ func requestReplacePayload(m map[string]string, input io.Reader) ([]byte, error)
 		return nil, ErrMissingTable
 	}
 
+	if err := validateTablePath(table); err != nil {
+		return nil, err
+	}
+
 	wdmp.Table = table
 
 	payload, err := ioutil.ReadAll(input)
@@ -344,11 +467,25 @@ func requestReplacePayload(m map[string]string, input io.Reader) ([]byte, error)
 		return nil, ErrMissingRows
 	}
 
-	err = json.Unmarshal(payload, &wdmp.Rows)
+	var rows map[string]map[string]json.RawMessage
+	err = strictUnmarshal(payload, &rows)
 	if err != nil {
 		return nil, ErrInvalidRows
 	}
 
+	wdmp.Rows = make(indexRow, len(rows))
+	for _, index := range slices.Sorted(maps.Keys(rows)) {
+		if err = validateRowIndex(index); err != nil {
+			return nil, err
+		}
+		if err = validateColumns(rows[index]); err != nil {
+			return nil, err
+		}
+		if wdmp.Rows[index], err = normalizeRow(rows[index]); err != nil {
+			return nil, err
+		}
+	}
+
 	return json.Marshal(wdmp)
 }
 
@@ -357,5 +494,8 @@ func requestDeletePayload(m map[string]string) ([]byte, error) {
 	if len(row) < 1 {
 		return nil, ErrMissingRow
 	}
+	if err := validateRowPath(row); err != nil {
+		return nil, err
+	}
 	return json.Marshal(&deleteRowDMP{Command: CommandDeleteRow, Row: row})
 }
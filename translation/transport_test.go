@@ -292,7 +292,7 @@ func TestRequestAddPayload(t *testing.T) {
 	t.Run("RowNotProvided", func(t *testing.T) {
 		assert := assert.New(t)
 
-		p, e := requestAddPayload(map[string]string{"parameter": "t0"}, bytes.NewBufferString(""))
+		p, e := requestAddPayload(map[string]string{"parameter": "t0."}, bytes.NewBufferString(""))
 
 		assert.Nil(p)
 		assert.EqualValues(ErrMissingRow, e)
@@ -301,7 +301,7 @@ func TestRequestAddPayload(t *testing.T) {
 	t.Run("RowInvalidProvided", func(t *testing.T) {
 		assert := assert.New(t)
 
-		p, e := requestAddPayload(map[string]string{"parameter": "t0"}, bytes.NewBufferString("invalid row"))
+		p, e := requestAddPayload(map[string]string{"parameter": "t0."}, bytes.NewBufferString("invalid row"))
 
 		assert.Nil(p)
 		assert.EqualValues(ErrInvalidRow, e)
@@ -309,13 +309,13 @@ func TestRequestAddPayload(t *testing.T) {
 
 	t.Run("IdealPath", func(t *testing.T) {
 		assert := assert.New(t)
-		p, e := requestAddPayload(map[string]string{"parameter": "t0"}, bytes.NewBufferString(`{"row": "r0"}`))
+		p, e := requestAddPayload(map[string]string{"parameter": "t0."}, bytes.NewBufferString(`{"row": "r0"}`))
 
 		assert.Nil(e)
 
 		expected, err := json.Marshal(&addRowWDMP{
 			Command: CommandAddRow,
-			Table:   "t0",
+			Table:   "t0.",
 			Row:     map[string]string{"row": "r0"},
 		})
 
@@ -339,7 +339,7 @@ func TestRequestReplacePayload(t *testing.T) {
 	t.Run("RowsNotProvided", func(t *testing.T) {
 		assert := assert.New(t)
 
-		p, e := requestReplacePayload(map[string]string{"parameter": "t0"}, bytes.NewBufferString(""))
+		p, e := requestReplacePayload(map[string]string{"parameter": "t0."}, bytes.NewBufferString(""))
 
 		assert.Nil(p)
 		assert.EqualValues(ErrMissingRows, e)
@@ -348,7 +348,7 @@ func TestRequestReplacePayload(t *testing.T) {
 	t.Run("RowsInvalidProvided", func(t *testing.T) {
 		assert := assert.New(t)
 
-		p, e := requestReplacePayload(map[string]string{"parameter": "t0"}, bytes.NewBufferString("invalid rows"))
+		p, e := requestReplacePayload(map[string]string{"parameter": "t0."}, bytes.NewBufferString("invalid rows"))
 
 		assert.Nil(p)
 		assert.EqualValues(ErrInvalidRows, e)
@@ -359,13 +359,13 @@ func TestRequestReplacePayload(t *testing.T) {
 
 		rowsPayload := `{"0": {"row": "r0"}}`
 
-		p, e := requestReplacePayload(map[string]string{"parameter": "t0"}, bytes.NewBufferString(rowsPayload))
+		p, e := requestReplacePayload(map[string]string{"parameter": "t0."}, bytes.NewBufferString(rowsPayload))
 
 		assert.Nil(e)
 
 		expected, err := json.Marshal(&replaceRowsWDMP{
 			Command: CommandReplaceRows,
-			Table:   "t0",
+			Table:   "t0.",
 			Rows:    indexRow{"0": map[string]string{"row": "r0"}},
 		})
 
@@ -390,13 +390,13 @@ func TestRequestDeletePayload(t *testing.T) {
 		assert := assert.New(t)
 
 		expected, err := json.Marshal(&deleteRowDMP{Command: CommandDeleteRow,
-			Row: "0",
+			Row: "t0.0.",
 		})
 		if err != nil {
 			panic(err)
 		}
 
-		p, e := requestDeletePayload(map[string]string{"parameter": "0"})
+		p, e := requestDeletePayload(map[string]string{"parameter": "t0.0."})
 
 		assert.Nil(e)
 		assert.EqualValues(expected, p)
@@ -4,11 +4,8 @@
 package translation
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 
 	"github.com/xmidt-org/sallust"
@@ -108,10 +105,10 @@ func wrap(WDMP []byte, tid string, pathVars map[string]string, partnerIDs []stri
 	}, nil
 }
 
-func decodeValidServiceRequest(services []string, decoder kithttp.DecodeRequestFunc) kithttp.DecodeRequestFunc {
+func decodeValidServiceRequest(services func() []string, decoder kithttp.DecodeRequestFunc) kithttp.DecodeRequestFunc {
 	return func(c context.Context, r *http.Request) (interface{}, error) {
 
-		if !contains(mux.Vars(r)["service"], services) {
+		if !contains(mux.Vars(r)["service"], services()) {
 			return nil, ErrInvalidService
 		}
 
@@ -122,7 +119,7 @@ func decodeValidServiceRequest(services []string, decoder kithttp.DecodeRequestF
 func loadWDMP(encodedWDMP []byte, newCID, oldCID, syncCMC string) (*setWDMP, error) {
 	wdmp := new(setWDMP)
 
-	err := json.Unmarshal(encodedWDMP, wdmp)
+	err := strictUnmarshal(encodedWDMP, wdmp)
 
 	if err != nil && len(encodedWDMP) > 0 { //len(encodedWDMP) == 0 is ok as it is used for TEST_SET
 		return nil, transaction.NewBadRequestError(fmt.Errorf("invalid WDMP structure: %s", err))
@@ -145,10 +142,8 @@ func captureWDMPParameters(ctx context.Context, r *http.Request) (nctx context.C
 	logger := sallust.Get(ctx)
 
 	if r.Method == http.MethodPatch {
-		bodyBytes, _ := ioutil.ReadAll(r.Body)
-		r.Body.Close()
-
-		r.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
+		// the body was read by captureRequestBody
+		bodyBytes, _ := getRequestBody(ctx)
 		wdmp, e := loadWDMP(bodyBytes, r.Header.Get(HeaderWPASyncNewCID), r.Header.Get(HeaderWPASyncOldCID), r.Header.Get(HeaderWPASyncCMC))
 		if e == nil {
 
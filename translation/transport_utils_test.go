@@ -179,7 +179,7 @@ func TestWrapInWRP(t *testing.T) {
 }
 
 func TestDecodeValidServiceRequest(t *testing.T) {
-	f := decodeValidServiceRequest([]string{"s0"}, func(_ context.Context, _ *http.Request) (interface{}, error) {
+	f := decodeValidServiceRequest(func() []string { return []string{"s0"} }, func(_ context.Context, _ *http.Request) (interface{}, error) {
 		return nil, nil
 	})
 
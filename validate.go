@@ -0,0 +1,443 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/xmidt-org/tr1d1um/group"
+	"github.com/xmidt-org/tr1d1um/schedule"
+	"github.com/xmidt-org/tr1d1um/stat"
+	"github.com/xmidt-org/tr1d1um/transaction"
+	"github.com/xmidt-org/tr1d1um/translation"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+// configProblem is a single invalid config value.
+type configProblem struct {
+	Key string
+	Err error
+}
+
+func (p configProblem) Error() string {
+	return fmt.Sprintf("%s: %s", p.Key, p.Err)
+}
+
+func (p configProblem) Unwrap() error {
+	return p.Err
+}
+
+// configValidator collects the problems found in a config.
+type configValidator struct {
+	v    *viper.Viper
+	errs error
+}
+
+func (cv *configValidator) fail(key string, format string, args ...interface{}) {
+	cv.errs = errors.Join(cv.errs, configProblem{Key: key, Err: fmt.Errorf(format, args...)})
+}
+
+// unmarshal decodes key into target, reporting whether it succeeded.
+func (cv *configValidator) unmarshal(key string, target interface{}) bool {
+	if err := cv.v.UnmarshalKey(key, target); err != nil {
+		cv.errs = errors.Join(cv.errs, configProblem{Key: key, Err: err})
+		return false
+	}
+	return true
+}
+
+// validateConfig checks every config section tr1d1um depends on and returns
+// all the problems found, each naming the key it applies to.
+func validateConfig(v *viper.Viper) error {
+	cv := &configValidator{v: v}
+
+	cv.validateURL(targetURLKey, v.GetString(targetURLKey))
+	if strings.TrimSpace(v.GetString(wrpSourceKey)) == "" {
+		cv.fail(wrpSourceKey, "must not be empty")
+	}
+	switch s := v.GetString(hooksSchemeKey); s {
+	case "http", "https":
+	default:
+		cv.fail(hooksSchemeKey, "unsupported scheme '%s'", s)
+	}
+
+	if _, err := loadRuntimeConfig(v); err != nil {
+		cv.errs = errors.Join(cv.errs, err)
+	}
+
+	var xmidtTimeout, argusTimeout httpClientTimeout
+	if cv.unmarshal("xmidtClientTimeout", &xmidtTimeout) {
+		cv.validateTimeouts("xmidtClientTimeout", xmidtTimeout)
+	}
+	if cv.unmarshal("argusClientTimeout", &argusTimeout) {
+		cv.validateTimeouts("argusClientTimeout", argusTimeout)
+	}
+
+	var tlsConfig outboundTLSConfig
+	if cv.unmarshal(xmidtClientTLSKey, &tlsConfig) {
+		cv.validateTLS(xmidtClientTLSKey, tlsConfig)
+	}
+
+	var reload configReloadConfig
+	if cv.unmarshal(configReloadKey, &reload) && reload.Interval < 0 {
+		cv.fail(configReloadKey+".interval", "must not be negative")
+	}
+
+	var ready readinessConfig
+	if cv.unmarshal(readinessKey, &ready) {
+		if ready.Interval < 0 {
+			cv.fail(readinessKey+".interval", "must not be negative")
+		}
+		if ready.Timeout < 0 {
+			cv.fail(readinessKey+".timeout", "must not be negative")
+		}
+	}
+
+	var drain drainConfig
+	if cv.unmarshal(drainKey, &drain) && drain.Timeout < 0 {
+		cv.fail(drainKey+".timeout", "must not be negative")
+	}
+
+	// requests to XMiDT never last longer than the client's request timeout
+	requestTimeout := configureXmidtClientTimeout(XmidtClientTimeoutConfigIn{XmidtClientTimeout: xmidtTimeout}).RequestTimeout
+	var timeouts transaction.TimeoutPolicy
+	if cv.unmarshal(requestTimeoutsKey, &timeouts) {
+		cv.validateTimeoutLimits(requestTimeoutsKey+".default", timeouts.Default, requestTimeout)
+		for _, s := range slices.Sorted(maps.Keys(timeouts.Services)) {
+			cv.validateTimeoutLimits(requestTimeoutsKey+".services."+s, timeouts.Services[s], requestTimeout)
+		}
+	}
+
+	var passthrough translation.WRPPassthrough
+	if cv.unmarshal(wrpPassthroughKey, &passthrough) {
+		for i, key := range passthrough.Metadata {
+			if strings.TrimPrefix(key, "/") == "" {
+				cv.fail(fmt.Sprintf("%s.metadata[%d]", wrpPassthroughKey, i), "must not be empty")
+			}
+		}
+		for i, name := range passthrough.Headers {
+			if name == "" || strings.Contains(name, ":") {
+				cv.fail(fmt.Sprintf("%s.headers[%d]", wrpPassthroughKey, i), "must be a non-empty header name without ':'")
+			}
+		}
+	}
+
+	var events translation.EventConfig
+	if cv.unmarshal(eventsKey, &events) && events.MaxDevices < 0 {
+		cv.fail(eventsKey+".maxDevices", "must not be negative")
+	}
+
+	var batchGet translation.BatchGetConfig
+	if cv.unmarshal(batchGetKey, &batchGet) {
+		if batchGet.PageSize < 0 {
+			cv.fail(batchGetKey+".pageSize", "must not be negative")
+		}
+		if batchGet.MaxPages < 0 {
+			cv.fail(batchGetKey+".maxPages", "must not be negative")
+		}
+		if batchGet.MaxResponseSize < 0 {
+			cv.fail(batchGetKey+".maxResponseSize", "must not be negative")
+		}
+		if batchGet.MinWildcardDepth < 0 {
+			cv.fail(batchGetKey+".minWildcardDepth", "must not be negative")
+		}
+	}
+
+	var bodyLimits translation.BodyLimits
+	if cv.unmarshal(bodyLimitsKey, &bodyLimits) {
+		if bodyLimits.Default < 0 {
+			cv.fail(bodyLimitsKey+".default", "must not be negative")
+		}
+		for _, m := range slices.Sorted(maps.Keys(bodyLimits.Methods)) {
+			if bodyLimits.Methods[m] < 0 {
+				cv.fail(bodyLimitsKey+".methods."+m, "must not be negative")
+			}
+		}
+	}
+
+	var schemas translation.TableSchemas
+	if cv.unmarshal(tableSchemasKey, &schemas) {
+		for i, ts := range schemas {
+			key := fmt.Sprintf("%s[%d]", tableSchemasKey, i)
+			if !strings.HasSuffix(ts.Table, ".") {
+				cv.fail(key+".table", "must be a table path ending with '.'")
+			}
+			if len(ts.Columns) == 0 {
+				cv.fail(key+".columns", "at least one column must be listed")
+			}
+		}
+	}
+
+	var scripts translation.ScriptConfig
+	if cv.unmarshal(scriptsKey, &scripts) && scripts.MaxSteps < 0 {
+		cv.fail(scriptsKey+".maxSteps", "must not be negative")
+	}
+
+	var cas translation.CompareAndSwapConfig
+	if cv.unmarshal(compareAndSwapKey, &cas) && cas.Retries != nil && *cas.Retries < 0 {
+		cv.fail(compareAndSwapKey+".retries", "must not be negative")
+	}
+
+	var sched schedule.Config
+	if cv.unmarshal(scheduleKey, &sched) {
+		if sched.PollInterval < 0 {
+			cv.fail(scheduleKey+".pollInterval", "must not be negative")
+		}
+		if sched.Jitter < 0 {
+			cv.fail(scheduleKey+".jitter", "must not be negative")
+		}
+		if sched.Concurrency < 0 {
+			cv.fail(scheduleKey+".concurrency", "must not be negative")
+		}
+		if sched.MaxDevices < 0 {
+			cv.fail(scheduleKey+".maxDevices", "must not be negative")
+		}
+		if sched.HistorySize < 0 {
+			cv.fail(scheduleKey+".historySize", "must not be negative")
+		}
+		if sched.MinEvery < 0 {
+			cv.fail(scheduleKey+".minEvery", "must not be negative")
+		}
+	}
+
+	var batchStat stat.BatchConfig
+	if cv.unmarshal(batchStatKey, &batchStat) {
+		if batchStat.MaxDevices < 0 {
+			cv.fail(batchStatKey+".maxDevices", "must not be negative")
+		}
+		if batchStat.Concurrency < 0 {
+			cv.fail(batchStatKey+".concurrency", "must not be negative")
+		}
+	}
+
+	var watch stat.WatchConfig
+	if cv.unmarshal(statWatchKey, &watch) {
+		if watch.Interval < 0 {
+			cv.fail(statWatchKey+".interval", "must not be negative")
+		}
+		if watch.IdleTimeout < 0 {
+			cv.fail(statWatchKey+".idleTimeout", "must not be negative")
+		}
+		if watch.LongPollTimeout < 0 {
+			cv.fail(statWatchKey+".longPollTimeout", "must not be negative")
+		}
+		if watch.MaxDevices < 0 {
+			cv.fail(statWatchKey+".maxDevices", "must not be negative")
+		}
+	}
+
+	var groups group.Config
+	if cv.unmarshal(groupsKey, &groups) {
+		if groups.MaxMembers < 0 {
+			cv.fail(groupsKey+".maxMembers", "must not be negative")
+		}
+		if groups.Concurrency < 0 {
+			cv.fail(groupsKey+".concurrency", "must not be negative")
+		}
+		for i, d := range groups.Inventory {
+			if _, err := wrp.ParseDeviceID(d.ID); err != nil {
+				cv.fail(fmt.Sprintf("%s.inventory[%d].id", groupsKey, i), "%w", err)
+			}
+		}
+	}
+
+	var acquirer authAcquirerConfig
+	hasAcquirer := v.IsSet(authAcquirerKey)
+	if hasAcquirer && cv.unmarshal(authAcquirerKey, &acquirer) {
+		cv.validateAuthAcquirer(acquirer)
+	}
+
+	var policy authPolicyConfig
+	if cv.unmarshal(authPolicyKey, &policy) {
+		cv.validateAuthPolicy(policy, hasAcquirer)
+	}
+
+	return cv.errs
+}
+
+// validateURL checks that u is an absolute http or https URL.
+func (cv *configValidator) validateURL(key, u string) {
+	parsed, err := url.Parse(u)
+	switch {
+	case err != nil:
+		cv.fail(key, "invalid URL: %w", err)
+	case parsed.Scheme != "http" && parsed.Scheme != "https":
+		cv.fail(key, "URL '%s' must have an http or https scheme", u)
+	case parsed.Host == "":
+		cv.fail(key, "URL '%s' must have a host", u)
+	}
+}
+
+func (cv *configValidator) validateTimeouts(key string, t httpClientTimeout) {
+	if t.ClientTimeout < 0 {
+		cv.fail(key+".clientTimeout", "must not be negative")
+	}
+	if t.RequestTimeout < 0 {
+		cv.fail(key+".requestTimeout", "must not be negative")
+	}
+	if t.NetDialerTimeout < 0 {
+		cv.fail(key+".netDialerTimeout", "must not be negative")
+	}
+}
+
+func (cv *configValidator) validateTimeoutLimits(key string, l transaction.TimeoutLimits, requestTimeout time.Duration) {
+	if l.Min < 0 {
+		cv.fail(key+".min", "must not be negative")
+	}
+	if l.Max < 0 {
+		cv.fail(key+".max", "must not be negative")
+	}
+	if l.Max > requestTimeout {
+		cv.fail(key+".max", "must not exceed xmidtClientTimeout.requestTimeout (%s)", requestTimeout)
+	}
+	if l.Min > 0 && l.Max > 0 && l.Min > l.Max {
+		cv.fail(key, "min %s is greater than max %s", l.Min, l.Max)
+	}
+}
+
+func (cv *configValidator) validateTLS(key string, c outboundTLSConfig) {
+	if c.ReloadInterval < 0 {
+		cv.fail(key+".reloadInterval", "must not be negative")
+	}
+	if _, err := newTLSConfig(c, nil); err != nil {
+		cv.fail(key, "%w", err)
+	}
+}
+
+func (cv *configValidator) validateAuthAcquirer(c authAcquirerConfig) {
+	const (
+		jwtKey = authAcquirerKey + ".JWT"
+		ccKey  = authAcquirerKey + ".clientCredentials"
+	)
+
+	jwt := c.JWT
+	hasJWT := jwt != (transaction.RemoteBearerTokenAcquirerOptions{})
+	if hasJWT {
+		if jwt.AuthURL == "" {
+			cv.fail(jwtKey+".authURL", "must be set")
+		} else {
+			cv.validateURL(jwtKey+".authURL", jwt.AuthURL)
+		}
+		cv.validatePositive(jwtKey+".timeout", jwt.Timeout)
+		cv.validatePositive(jwtKey+".buffer", jwt.Buffer)
+		if jwt.Fallback < 0 {
+			cv.fail(jwtKey+".fallback", "must not be negative")
+		}
+	}
+
+	cc := c.ClientCredentials
+	hasCC := cc.TokenURL != "" || cc.ClientID != "" || cc.ClientSecret != "" ||
+		len(cc.Scopes) > 0 || cc.Audience != "" || cc.AuthStyle != "" ||
+		cc.Timeout != 0 || cc.Buffer != 0 || cc.Fallback != 0
+	if hasCC {
+		if cc.TokenURL == "" {
+			cv.fail(ccKey+".tokenURL", "must be set")
+		} else {
+			cv.validateURL(ccKey+".tokenURL", cc.TokenURL)
+		}
+		if cc.ClientID == "" {
+			cv.fail(ccKey+".clientID", "must be set")
+		}
+		switch cc.AuthStyle {
+		case "", transaction.ClientAuthBasic, transaction.ClientAuthForm:
+		default:
+			cv.fail(ccKey+".authStyle", "unsupported client auth style '%s'", cc.AuthStyle)
+		}
+		cv.validatePositive(ccKey+".timeout", cc.Timeout)
+		cv.validatePositive(ccKey+".buffer", cc.Buffer)
+		if cc.Fallback < 0 {
+			cv.fail(ccKey+".fallback", "must not be negative")
+		}
+	}
+
+	if c.Basic != "" && !strings.HasPrefix(c.Basic, "Basic ") {
+		cv.fail(authAcquirerKey+".Basic", "must be of the form 'Basic xyz=='")
+	}
+
+	if !hasJWT && !hasCC && c.Basic == "" {
+		cv.fail(authAcquirerKey, "one of JWT, clientCredentials or Basic must be configured")
+	}
+
+	cv.validateTLS(authAcquirerKey+".TLS", c.TLS)
+}
+
+func (cv *configValidator) validatePositive(key string, d time.Duration) {
+	if d <= 0 {
+		cv.fail(key, "must be positive")
+	}
+}
+
+func (cv *configValidator) validateAuthPolicy(c authPolicyConfig, hasAcquirer bool) {
+	modes := map[string]string{authPolicyKey + ".default": c.Default}
+	for _, api := range slices.Sorted(maps.Keys(c.APIs)) {
+		if api != "device" && api != "stat" {
+			cv.fail(authPolicyKey+".apis."+api, "unknown API, must be 'device' or 'stat'")
+			continue
+		}
+		modes[authPolicyKey+".apis."+api] = c.APIs[api]
+	}
+	for service, mode := range c.Services {
+		modes[authPolicyKey+".services."+service] = mode
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(modes)) {
+		switch mode := modes[key]; mode {
+		case "", transaction.AuthModePassThrough:
+		case transaction.AuthModeService:
+			if !hasAcquirer {
+				cv.fail(key, "service auth mode requires %s", authAcquirerKey)
+			}
+		case transaction.AuthModeExchange:
+			if c.Exchange.URL == "" {
+				cv.fail(key, "exchange auth mode requires %s.exchange.url", authPolicyKey)
+			}
+		default:
+			cv.fail(key, "unknown auth mode '%s'", mode)
+		}
+	}
+
+	if c.Exchange.URL != "" {
+		cv.validateURL(authPolicyKey+".exchange.url", c.Exchange.URL)
+		cv.validatePositive(authPolicyKey+".exchange.timeout", c.Exchange.Timeout)
+		if c.Exchange.Buffer < 0 {
+			cv.fail(authPolicyKey+".exchange.buffer", "must not be negative")
+		}
+	}
+
+	cv.validateTLS(authPolicyKey+".TLS", c.TLS)
+}
+
+// configProblems flattens the problems returned by validateConfig.
+func configProblems(err error) []string {
+	if err == nil {
+		return nil
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var problems []string
+		for _, e := range joined.Unwrap() {
+			problems = append(problems, configProblems(e)...)
+		}
+		return problems
+	}
+
+	return []string{err.Error()}
+}
+
+// printConfigProblems writes one line per problem found in the config.
+func printConfigProblems(w io.Writer, err error) {
+	problems := configProblems(err)
+	fmt.Fprintf(w, "invalid configuration, %d problem(s) found:\n", len(problems))
+	for _, p := range problems {
+		fmt.Fprintf(w, "  - %s\n", p)
+	}
+}
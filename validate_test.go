@@ -0,0 +1,235 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfig(t *testing.T) {
+	tcs := []struct {
+		name      string
+		config    string
+		expectErr []string
+	}{
+		{
+			name:   "defaults with services",
+			config: `supportedServices: ["config"]`,
+		},
+		{
+			name: "complete auth",
+			config: `
+supportedServices: ["config"]
+authAcquirer:
+  clientCredentials:
+    tokenURL: "https://auth.example/oauth2/token"
+    clientID: "tr1d1um"
+    timeout: "10s"
+    buffer: "1m"
+authPolicy:
+  default: "service"
+  services:
+    iot: "exchange"
+  exchange:
+    url: "https://auth.example/exchange"
+    timeout: "10s"
+`,
+		},
+		{
+			name:   "missing targetURL scheme and services",
+			config: `targetURL: "scytale:6300/api/v3"`,
+			expectErr: []string{
+				"targetURL: URL 'scytale:6300/api/v3' must have an http or https scheme",
+				"supportedServices: at least one service must be listed",
+			},
+		},
+		{
+			name: "half-filled authAcquirer",
+			config: `
+supportedServices: ["config"]
+authAcquirer:
+  JWT:
+    authURL: "auth.example/token"
+  clientCredentials:
+    clientID: "tr1d1um"
+    authStyle: "header"
+    timeout: "10s"
+  Basic: "dXNlcjpwYXNz"
+`,
+			expectErr: []string{
+				"authAcquirer.JWT.authURL: URL 'auth.example/token' must have an http or https scheme",
+				"authAcquirer.JWT.timeout: must be positive",
+				"authAcquirer.JWT.buffer: must be positive",
+				"authAcquirer.clientCredentials.tokenURL: must be set",
+				"authAcquirer.clientCredentials.authStyle: unsupported client auth style 'header'",
+				"authAcquirer.clientCredentials.buffer: must be positive",
+				"authAcquirer.Basic: must be of the form 'Basic xyz=='",
+			},
+		},
+		{
+			name: "empty authAcquirer",
+			config: `
+supportedServices: ["config"]
+authAcquirer:
+  TLS:
+    minVersion: "1.3"
+`,
+			expectErr: []string{"authAcquirer: one of JWT, clientCredentials or Basic must be configured"},
+		},
+		{
+			name: "invalid authPolicy",
+			config: `
+supportedServices: ["config"]
+authPolicy:
+  default: "service"
+  apis:
+    hooks: "passthrough"
+    stat: "anonymous"
+  services:
+    config: "exchange"
+`,
+			expectErr: []string{
+				"authPolicy.default: service auth mode requires authAcquirer",
+				"authPolicy.apis.hooks: unknown API, must be 'device' or 'stat'",
+				"authPolicy.apis.stat: unknown auth mode 'anonymous'",
+				"authPolicy.services.config: exchange auth mode requires authPolicy.exchange.url",
+			},
+		},
+		{
+			name: "invalid TLS and timeouts",
+			config: `
+supportedServices: ["config"]
+hooksScheme: "ftp"
+WRPSource: " "
+xmidtClientTimeout:
+  clientTimeout: "-1s"
+xmidtClientTLS:
+  certificateFile: "/etc/tr1d1um/client.crt"
+  minVersion: "1.1"
+configReload:
+  interval: "-1m"
+`,
+			expectErr: []string{
+				"WRPSource: must not be empty",
+				"hooksScheme: unsupported scheme 'ftp'",
+				"xmidtClientTimeout.clientTimeout: must not be negative",
+				"xmidtClientTLS: both certificateFile and keyFile must be set",
+				"configReload.interval: must not be negative",
+			},
+		},
+		{
+			name: "request timeouts beyond the XMiDT request timeout",
+			config: `
+supportedServices: ["config"]
+xmidtClientTimeout:
+  requestTimeout: 60s
+requestTimeouts:
+  default:
+    max: 30s
+  services:
+    config:
+      min: 10s
+      max: 90s
+    stat:
+      min: 20s
+      max: 10s
+`,
+			expectErr: []string{
+				"requestTimeouts.services.config.max: must not exceed xmidtClientTimeout.requestTimeout (1m0s)",
+				"requestTimeouts.services.stat: min 20s is greater than max 10s",
+			},
+		},
+		{
+			name: "invalid wrpPassthrough",
+			config: `
+supportedServices: ["config"]
+wrpPassthrough:
+  metadata: ["/trust", "/"]
+  headers: ["x-priority", "bad: name"]
+events:
+  maxDevices: -1
+batchGet:
+  pageSize: -1
+  minWildcardDepth: -1
+tableSchemas:
+  - table: "Device.WiFi.AccessPoint"
+    columns: ["SSID"]
+scripts:
+  maxSteps: -1
+compareAndSwap:
+  retries: -1
+schedule:
+  jitter: -1s
+  minEvery: -1m
+batchStat:
+  maxDevices: -1
+statWatch:
+  interval: -1s
+groups:
+  concurrency: -1
+  inventory:
+    - id: "mac:112233445566"
+    - id: "nope"
+`,
+			expectErr: []string{
+				"wrpPassthrough.metadata[1]: must not be empty",
+				"wrpPassthrough.headers[1]: must be a non-empty header name without ':'",
+				"events.maxDevices: must not be negative",
+				"batchGet.pageSize: must not be negative",
+				"batchGet.minWildcardDepth: must not be negative",
+				"tableSchemas[0].table: must be a table path ending with '.'",
+				"scripts.maxSteps: must not be negative",
+				"compareAndSwap.retries: must not be negative",
+				"schedule.jitter: must not be negative",
+				"schedule.minEvery: must not be negative",
+				"batchStat.maxDevices: must not be negative",
+				"statWatch.interval: must not be negative",
+				"groups.concurrency: must not be negative",
+				"groups.inventory[1].id: invalid device name",
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			v := viper.New()
+			for k, va := range defaults {
+				v.SetDefault(k, va)
+			}
+			v.SetConfigType("yaml")
+			require.NoError(t, v.ReadConfig(strings.NewReader(tc.config)))
+
+			err := validateConfig(v)
+			if len(tc.expectErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			problems := configProblems(err)
+			assert.Len(t, problems, len(tc.expectErr))
+			for _, e := range tc.expectErr {
+				assert.Contains(t, problems, e)
+			}
+		})
+	}
+}
+
+func TestPrintConfigProblems(t *testing.T) {
+	v := viper.New()
+	v.Set(targetURLKey, "scytale")
+
+	var b bytes.Buffer
+	printConfigProblems(&b, validateConfig(v))
+	out := b.String()
+	assert.True(t, strings.HasPrefix(out, "invalid configuration, "))
+	assert.Contains(t, out, "  - targetURL: URL 'scytale' must have an http or https scheme\n")
+	assert.Contains(t, out, "  - supportedServices: at least one service must be listed\n")
+}